@@ -0,0 +1,213 @@
+package assets
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"google.golang.org/api/docs/v1"
+)
+
+const (
+	assetsDirPerm = 0755
+	assetFilePerm = 0644
+	hashPrefixLen = 16
+)
+
+// Failure describes an embedded object that could not be exported
+type Failure struct {
+	ObjectID string `json:"objectId"`
+	Reason   string `json:"reason"`
+}
+
+// Result holds the outcome of an asset export
+type Result struct {
+	Failures []Failure
+	Paths    map[string]string
+}
+
+// Export downloads the image of every inline and positioned object of a
+// document's body, table cells included, into dir. Files are named after the
+// hash of their content so identical images are only written once. The returned
+// paths are relative to base, the directory of the file referencing them, and
+// use forward slashes so they can be referenced from markdown directly.
+func Export(ctx context.Context, client *http.Client, doc *docs.Document, dir string, base string) (*Result, error) {
+	if err := os.MkdirAll(dir, assetsDirPerm); err != nil {
+		return nil, fmt.Errorf("error creating assets directory: %w", err)
+	}
+
+	result := &Result{Paths: map[string]string{}}
+	ids, objects := embeddedObjects(doc)
+
+	for _, id := range ids {
+		object := objects[id]
+		if object == nil || object.ImageProperties == nil || object.ImageProperties.ContentUri == "" {
+			result.Failures = append(result.Failures, Failure{ObjectID: id, Reason: "object has no downloadable content"})
+			continue
+		}
+
+		path, err := download(ctx, client, object.ImageProperties.ContentUri, dir)
+		if err != nil {
+			result.Failures = append(result.Failures, Failure{ObjectID: id, Reason: err.Error()})
+			continue
+		}
+
+		link, err := relativePath(base, path)
+		if err != nil {
+			result.Failures = append(result.Failures, Failure{ObjectID: id, Reason: err.Error()})
+			continue
+		}
+
+		result.Paths[id] = link
+	}
+
+	return result, nil
+}
+
+// embeddedObjects returns the IDs of the objects referenced from the body, in
+// reading order, along with their embedded objects. Table cells are walked in
+// the same pass as body paragraphs.
+func embeddedObjects(doc *docs.Document) ([]string, map[string]*docs.EmbeddedObject) {
+	var ids []string
+	objects := map[string]*docs.EmbeddedObject{}
+
+	add := func(id string, object *docs.EmbeddedObject) {
+		if _, ok := objects[id]; !ok {
+			ids = append(ids, id)
+			objects[id] = object
+		}
+	}
+
+	var walk func(content []*docs.StructuralElement)
+	walk = func(content []*docs.StructuralElement) {
+		for _, element := range content {
+			switch {
+			case element.Paragraph != nil:
+				for _, e := range element.Paragraph.Elements {
+					if e.InlineObjectElement == nil {
+						continue
+					}
+					id := e.InlineObjectElement.InlineObjectId
+					if object, ok := doc.InlineObjects[id]; ok && object.InlineObjectProperties != nil {
+						add(id, object.InlineObjectProperties.EmbeddedObject)
+					} else {
+						add(id, nil)
+					}
+				}
+
+				for _, id := range element.Paragraph.PositionedObjectIds {
+					if object, ok := doc.PositionedObjects[id]; ok && object.PositionedObjectProperties != nil {
+						add(id, object.PositionedObjectProperties.EmbeddedObject)
+					} else {
+						add(id, nil)
+					}
+				}
+			case element.Table != nil:
+				for _, row := range element.Table.TableRows {
+					for _, cell := range row.TableCells {
+						walk(cell.Content)
+					}
+				}
+			}
+		}
+	}
+
+	if doc.Body != nil {
+		walk(doc.Body.Content)
+	}
+
+	return ids, objects
+}
+
+// relativePath returns path relative to the base directory, with forward slashes
+func relativePath(base string, path string) (string, error) {
+	absBase, err := filepath.Abs(base)
+	if err != nil {
+		return "", fmt.Errorf("error resolving %s: %w", base, err)
+	}
+
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return "", fmt.Errorf("error resolving %s: %w", path, err)
+	}
+
+	rel, err := filepath.Rel(absBase, absPath)
+	if err != nil {
+		return "", fmt.Errorf("error resolving %s: %w", path, err)
+	}
+
+	return filepath.ToSlash(rel), nil
+}
+
+func download(ctx context.Context, client *http.Client, uri string, dir string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return "", fmt.Errorf("invalid content URI: %w", err)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("download failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("download failed: %s", resp.Status)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("download failed: %w", err)
+	}
+
+	sum := sha256.Sum256(data)
+	name := hex.EncodeToString(sum[:])[:hashPrefixLen] + extension(resp.Header.Get("Content-Type"))
+	path := filepath.Join(dir, name)
+
+	if _, err := os.Stat(path); err == nil {
+		return path, nil
+	}
+
+	if err := os.WriteFile(path, data, assetFilePerm); err != nil {
+		return "", fmt.Errorf("error writing %s: %w", path, err)
+	}
+
+	return path, nil
+}
+
+func extension(contentType string) string {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return ".bin"
+	}
+
+	switch mediaType {
+	case "image/png":
+		return ".png"
+	case "image/jpeg":
+		return ".jpg"
+	case "image/gif":
+		return ".gif"
+	case "image/svg+xml":
+		return ".svg"
+	case "image/webp":
+		return ".webp"
+	}
+
+	if extensions, err := mime.ExtensionsByType(mediaType); err == nil && len(extensions) > 0 {
+		return extensions[0]
+	}
+
+	if strings.HasPrefix(mediaType, "image/") {
+		return "." + strings.TrimPrefix(mediaType, "image/")
+	}
+
+	return ".bin"
+}
@@ -0,0 +1,255 @@
+package ast
+
+import (
+	_ "embed"
+	"fmt"
+	"strings"
+
+	"google-docs-manager/internal/conversion"
+	"google.golang.org/api/docs/v1"
+)
+
+// SchemaVersion is the version of the document model emitted and accepted by this package
+const SchemaVersion = 1
+
+// Schema is the JSON Schema describing the document model
+//
+//go:embed schema.json
+var Schema []byte
+
+// Block types
+const (
+	BlockHeading         = "heading"
+	BlockListItem        = "listItem"
+	BlockParagraph       = "paragraph"
+	BlockSectionBreak    = "sectionBreak"
+	BlockTable           = "table"
+	BlockTableOfContents = "tableOfContents"
+	BlockTitle           = "title"
+)
+
+// Span types
+const (
+	SpanEquation       = "equation"
+	SpanFootnote       = "footnote"
+	SpanHorizontalRule = "horizontalRule"
+	SpanImage          = "image"
+	SpanPerson         = "person"
+	SpanRichLink       = "richLink"
+	SpanText           = "text"
+)
+
+// Document is the root of the simplified document model
+type Document struct {
+	Blocks     []*Block `json:"blocks"`
+	DocumentID string   `json:"documentId,omitempty"`
+	Title      string   `json:"title"`
+	Version    int      `json:"version"`
+}
+
+// Block is a paragraph-level element of the document
+type Block struct {
+	EndIndex   int64    `json:"endIndex,omitempty"`
+	HeadingID  string   `json:"headingId,omitempty"`
+	Level      int      `json:"level,omitempty"`
+	List       *List    `json:"list,omitempty"`
+	Rows       [][]Cell `json:"rows,omitempty"`
+	Spans      []*Span  `json:"spans,omitempty"`
+	StartIndex int64    `json:"startIndex,omitempty"`
+	Type       string   `json:"type"`
+}
+
+// List describes the list a list item belongs to
+type List struct {
+	ID      string `json:"id"`
+	Level   int    `json:"level"`
+	Ordered bool   `json:"ordered"`
+}
+
+// Cell is a table cell holding its own blocks
+type Cell struct {
+	Blocks []*Block `json:"blocks"`
+}
+
+// Span is an inline run of content with a uniform style
+type Span struct {
+	Baseline      string `json:"baseline,omitempty"`
+	Bold          bool   `json:"bold,omitempty"`
+	Code          bool   `json:"code,omitempty"`
+	Description   string `json:"description,omitempty"`
+	Email         string `json:"email,omitempty"`
+	EndIndex      int64  `json:"endIndex,omitempty"`
+	FootnoteID    string `json:"footnoteId,omitempty"`
+	Italic        bool   `json:"italic,omitempty"`
+	Link          string `json:"link,omitempty"`
+	ObjectID      string `json:"objectId,omitempty"`
+	SourceURI     string `json:"sourceUri,omitempty"`
+	StartIndex    int64  `json:"startIndex,omitempty"`
+	Strikethrough bool   `json:"strikethrough,omitempty"`
+	Text          string `json:"text,omitempty"`
+	Type          string `json:"type"`
+	Underline     bool   `json:"underline,omitempty"`
+}
+
+// FromDocument builds the simplified model of a Google Doc
+func FromDocument(doc *docs.Document) *Document {
+	return &Document{
+		Blocks:     blocksFromContent(doc, doc.Body.Content),
+		DocumentID: doc.DocumentId,
+		Title:      doc.Title,
+		Version:    SchemaVersion,
+	}
+}
+
+func blocksFromContent(doc *docs.Document, content []*docs.StructuralElement) []*Block {
+	var blocks []*Block
+
+	for _, element := range content {
+		switch {
+		case element.Paragraph != nil:
+			blocks = append(blocks, blockFromParagraph(doc, element))
+		case element.Table != nil:
+			block := &Block{
+				EndIndex:   element.EndIndex,
+				StartIndex: element.StartIndex,
+				Type:       BlockTable,
+			}
+			for _, row := range element.Table.TableRows {
+				cells := make([]Cell, 0, len(row.TableCells))
+				for _, cell := range row.TableCells {
+					cells = append(cells, Cell{Blocks: blocksFromContent(doc, cell.Content)})
+				}
+				block.Rows = append(block.Rows, cells)
+			}
+			blocks = append(blocks, block)
+		case element.TableOfContents != nil:
+			blocks = append(blocks, &Block{
+				EndIndex:   element.EndIndex,
+				StartIndex: element.StartIndex,
+				Type:       BlockTableOfContents,
+			})
+		case element.SectionBreak != nil && element.StartIndex > 0:
+			blocks = append(blocks, &Block{
+				EndIndex:   element.EndIndex,
+				StartIndex: element.StartIndex,
+				Type:       BlockSectionBreak,
+			})
+		}
+	}
+
+	return blocks
+}
+
+func blockFromParagraph(doc *docs.Document, element *docs.StructuralElement) *Block {
+	paragraph := element.Paragraph
+	block := &Block{
+		EndIndex:   element.EndIndex,
+		StartIndex: element.StartIndex,
+		Type:       BlockParagraph,
+	}
+
+	if style := paragraph.ParagraphStyle; style != nil {
+		switch {
+		case style.NamedStyleType == "TITLE":
+			block.Type = BlockTitle
+		case strings.HasPrefix(style.NamedStyleType, "HEADING_"):
+			block.Type = BlockHeading
+			fmt.Sscanf(style.NamedStyleType, "HEADING_%d", &block.Level)
+		}
+		block.HeadingID = style.HeadingId
+	}
+
+	if paragraph.Bullet != nil {
+		block.Type = BlockListItem
+		block.List = &List{
+			ID:      paragraph.Bullet.ListId,
+			Level:   int(paragraph.Bullet.NestingLevel),
+			Ordered: conversion.IsOrderedList(doc, paragraph.Bullet),
+		}
+	}
+
+	for _, pe := range paragraph.Elements {
+		if span := spanFromElement(doc, pe); span != nil {
+			block.Spans = append(block.Spans, span)
+		}
+	}
+
+	return block
+}
+
+func spanFromElement(doc *docs.Document, element *docs.ParagraphElement) *Span {
+	span := &Span{
+		EndIndex:   element.EndIndex,
+		StartIndex: element.StartIndex,
+	}
+
+	switch {
+	case element.TextRun != nil:
+		text := strings.TrimSuffix(element.TextRun.Content, "\n")
+		if text == "" {
+			return nil
+		}
+		span.Type = SpanText
+		span.Text = text
+		applyTextStyle(span, element.TextRun.TextStyle)
+	case element.InlineObjectElement != nil:
+		span.Type = SpanImage
+		span.ObjectID = element.InlineObjectElement.InlineObjectId
+		if object, ok := doc.InlineObjects[span.ObjectID]; ok && object.InlineObjectProperties != nil {
+			if embedded := object.InlineObjectProperties.EmbeddedObject; embedded != nil {
+				span.Text = embedded.Title
+				span.Description = embedded.Description
+				if embedded.ImageProperties != nil {
+					span.SourceURI = embedded.ImageProperties.SourceUri
+				}
+			}
+		}
+	case element.Person != nil:
+		span.Type = SpanPerson
+		if element.Person.PersonProperties != nil {
+			span.Text = element.Person.PersonProperties.Name
+			span.Email = element.Person.PersonProperties.Email
+		}
+	case element.RichLink != nil:
+		span.Type = SpanRichLink
+		if element.RichLink.RichLinkProperties != nil {
+			span.Text = element.RichLink.RichLinkProperties.Title
+			span.Link = element.RichLink.RichLinkProperties.Uri
+		}
+	case element.FootnoteReference != nil:
+		span.Type = SpanFootnote
+		span.FootnoteID = element.FootnoteReference.FootnoteId
+		span.Text = element.FootnoteReference.FootnoteNumber
+	case element.Equation != nil:
+		span.Type = SpanEquation
+	case element.HorizontalRule != nil:
+		span.Type = SpanHorizontalRule
+	default:
+		return nil
+	}
+
+	return span
+}
+
+func applyTextStyle(span *Span, style *docs.TextStyle) {
+	if style == nil {
+		return
+	}
+
+	span.Bold = style.Bold
+	span.Code = conversion.IsMonospace(style)
+	span.Italic = style.Italic
+	span.Strikethrough = style.Strikethrough
+	span.Underline = style.Underline && style.Link == nil
+
+	switch style.BaselineOffset {
+	case "SUPERSCRIPT":
+		span.Baseline = "superscript"
+	case "SUBSCRIPT":
+		span.Baseline = "subscript"
+	}
+
+	if style.Link != nil {
+		span.Link = conversion.LinkTarget(style.Link)
+	}
+}
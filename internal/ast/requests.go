@@ -0,0 +1,384 @@
+package ast
+
+import (
+	"fmt"
+	"strings"
+
+	"google-docs-manager/internal/document"
+
+	"google.golang.org/api/docs/v1"
+)
+
+// objectPlaceholder reserves the single index of a chip or image until it is replaced
+const objectPlaceholder = "￼"
+
+// codeFontFamily is the font applied to code spans
+const codeFontFamily = "Courier New"
+
+// ToRequests converts a document model into Docs API requests inserting its
+// content at startIndex. Elements the API cannot create (tables of contents,
+// equations, footnotes, horizontal rules and images without a source URI) are skipped.
+func ToRequests(d *Document, startIndex int64) ([]*docs.Request, error) {
+	if d.Version != SchemaVersion {
+		return nil, fmt.Errorf("unsupported document model version %d (expected %d)", d.Version, SchemaVersion)
+	}
+
+	b := &requestBuilder{index: startIndex}
+	for i, block := range d.Blocks {
+		if err := b.addBlock(block); err != nil {
+			return nil, fmt.Errorf("block %d: %w", i, err)
+		}
+	}
+
+	// Creating bullets removes the leading tabs used for nesting, so lists are
+	// created last and from the end of the document to keep earlier indices valid
+	requests := b.requests
+	for i := len(b.lists) - 1; i >= 0; i-- {
+		requests = append(requests, b.lists[i])
+	}
+
+	return requests, nil
+}
+
+type requestBuilder struct {
+	index    int64
+	lastList string
+	lists    []*docs.Request
+	requests []*docs.Request
+}
+
+func (b *requestBuilder) addBlock(block *Block) error {
+	switch block.Type {
+	case BlockTitle, BlockHeading, BlockParagraph, BlockListItem:
+		return b.addParagraph(block)
+	case BlockTable:
+		b.lastList = ""
+		return b.addTable(block)
+	case BlockSectionBreak:
+		b.lastList = ""
+		b.requests = append(b.requests, &docs.Request{
+			InsertSectionBreak: &docs.InsertSectionBreakRequest{
+				Location:    &docs.Location{Index: b.index},
+				SectionType: "CONTINUOUS",
+			},
+		})
+		b.index += 2
+		return nil
+	case BlockTableOfContents:
+		return nil
+	}
+
+	return fmt.Errorf("unknown block type: %s", block.Type)
+}
+
+func (b *requestBuilder) addParagraph(block *Block) error {
+	namedStyle, prefix, err := paragraphLayout(block)
+	if err != nil {
+		return err
+	}
+
+	start := b.index
+	text, styles := spansToText(block.Spans, start+document.TextLength(prefix))
+	text = prefix + text
+	textLen := document.TextLength(text)
+
+	b.requests = append(b.requests, &docs.Request{
+		InsertText: &docs.InsertTextRequest{
+			Location: &docs.Location{Index: start},
+			Text:     text + "\n",
+		},
+	}, paragraphStyleRequest(namedStyle, start, start+textLen+1))
+	b.requests = append(b.requests, styles...)
+	b.index += textLen + 1
+
+	b.lists, b.lastList = addBullets(b.lists, b.lastList, block, start, b.index)
+	return nil
+}
+
+// paragraphLayout returns the named style of a paragraph-like block and the
+// tabs setting its nesting level when it is a list item
+func paragraphLayout(block *Block) (string, string, error) {
+	namedStyle := "NORMAL_TEXT"
+	switch block.Type {
+	case BlockTitle:
+		namedStyle = "TITLE"
+	case BlockHeading:
+		if block.Level < 1 || block.Level > 6 {
+			return "", "", fmt.Errorf("invalid heading level: %d (must be 1-6)", block.Level)
+		}
+		namedStyle = fmt.Sprintf("HEADING_%d", block.Level)
+	}
+
+	prefix := ""
+	if block.Type == BlockListItem {
+		if block.List == nil {
+			return "", "", fmt.Errorf("list item without list")
+		}
+		prefix = strings.Repeat("\t", block.List.Level)
+	}
+
+	return namedStyle, prefix, nil
+}
+
+func paragraphStyleRequest(namedStyle string, start int64, end int64) *docs.Request {
+	return &docs.Request{
+		UpdateParagraphStyle: &docs.UpdateParagraphStyleRequest{
+			Fields:         "namedStyleType",
+			ParagraphStyle: &docs.ParagraphStyle{NamedStyleType: namedStyle},
+			Range: &docs.Range{
+				EndIndex:   end,
+				StartIndex: start,
+			},
+		},
+	}
+}
+
+// addBullets records the bullets of a list item spanning [start, end), given
+// the bullets requests so far and the list of the previous block. Consecutive
+// items of the same list share one request; other blocks end the current list.
+func addBullets(lists []*docs.Request, lastList string, block *Block, start int64, end int64) ([]*docs.Request, string) {
+	if block.Type != BlockListItem {
+		return lists, ""
+	}
+
+	if lastList == block.List.ID && len(lists) > 0 {
+		lists[len(lists)-1].CreateParagraphBullets.Range.EndIndex = end
+		return lists, lastList
+	}
+
+	preset := "BULLET_DISC_CIRCLE_SQUARE"
+	if block.List.Ordered {
+		preset = "NUMBERED_DECIMAL_ALPHA_ROMAN"
+	}
+
+	return append(lists, &docs.Request{
+		CreateParagraphBullets: &docs.CreateParagraphBulletsRequest{
+			BulletPreset: preset,
+			Range: &docs.Range{
+				EndIndex:   end,
+				StartIndex: start,
+			},
+		},
+	}), block.List.ID
+}
+
+func (b *requestBuilder) addTable(block *Block) error {
+	rows := len(block.Rows)
+	columns := 0
+	for _, row := range block.Rows {
+		columns = max(columns, len(row))
+	}
+
+	if rows == 0 || columns == 0 {
+		return fmt.Errorf("table has no cells")
+	}
+
+	tableIndex := b.index
+	b.requests = append(b.requests, &docs.Request{
+		InsertTable: &docs.InsertTableRequest{
+			Columns:  int64(columns),
+			Location: &docs.Location{Index: tableIndex},
+			Rows:     int64(rows),
+		},
+	})
+
+	// A newline precedes the table, then each row and each cell take one index
+	// and every empty cell holds a single newline. Cells are filled from the last
+	// one so the computed indices of earlier cells stay valid.
+	inserted := int64(0)
+	for r := rows - 1; r >= 0; r-- {
+		for c := columns - 1; c >= 0; c-- {
+			if c >= len(block.Rows[r]) {
+				continue
+			}
+
+			cellIndex := tableIndex + 4 + int64(r)*int64(2*columns+1) + int64(2*c)
+
+			text, styles, tabs, err := cellRequests(block.Rows[r][c].Blocks, cellIndex)
+			if err != nil {
+				return fmt.Errorf("row %d, cell %d: %w", r, c, err)
+			}
+			if text == "" {
+				continue
+			}
+
+			b.requests = append(b.requests, &docs.Request{
+				InsertText: &docs.InsertTextRequest{
+					Location: &docs.Location{Index: cellIndex},
+					Text:     text,
+				},
+			})
+			b.requests = append(b.requests, styles...)
+			inserted += document.TextLength(text) - tabs
+		}
+	}
+
+	b.index = tableIndex + 2 + int64(rows)*int64(2*columns+1) + inserted
+	return nil
+}
+
+// cellRequests lays out the blocks of a table cell as the text filling it and
+// the requests styling that text once inserted at index. The last block takes
+// the newline the cell already holds. Creating bullets removes the nesting tabs,
+// so their number is returned too.
+func cellRequests(blocks []*Block, index int64) (string, []*docs.Request, int64, error) {
+	var parts []string
+	var requests []*docs.Request
+	var lists []*docs.Request
+	var lastList string
+	tabs := int64(0)
+	offset := index
+
+	for i, block := range blocks {
+		switch block.Type {
+		case BlockTitle, BlockHeading, BlockParagraph, BlockListItem:
+		default:
+			return "", nil, 0, fmt.Errorf("block %d: %s blocks cannot be placed in a table cell", i, block.Type)
+		}
+
+		namedStyle, prefix, err := paragraphLayout(block)
+		if err != nil {
+			return "", nil, 0, fmt.Errorf("block %d: %w", i, err)
+		}
+
+		text, styles := spansToText(block.Spans, offset+document.TextLength(prefix))
+		text = prefix + text
+		textLen := document.TextLength(text)
+
+		parts = append(parts, text)
+		requests = append(requests, paragraphStyleRequest(namedStyle, offset, offset+textLen+1))
+		requests = append(requests, styles...)
+		lists, lastList = addBullets(lists, lastList, block, offset, offset+textLen+1)
+		tabs += document.TextLength(prefix)
+		offset += textLen + 1
+	}
+
+	for i := len(lists) - 1; i >= 0; i-- {
+		requests = append(requests, lists[i])
+	}
+
+	return strings.Join(parts, "\n"), requests, tabs, nil
+}
+
+// spansToText concatenates the spans of a block and returns the requests styling
+// them once the text is inserted at startIndex
+func spansToText(spans []*Span, startIndex int64) (string, []*docs.Request) {
+	var text strings.Builder
+	var styles []*docs.Request
+	var objects []*docs.Request
+	offset := startIndex
+
+	for _, span := range spans {
+		var content string
+
+		switch span.Type {
+		case SpanText, SpanRichLink:
+			content = span.Text
+			if content == "" {
+				content = span.Link
+			}
+		case SpanPerson:
+			if span.Email == "" {
+				continue
+			}
+			content = objectPlaceholder
+			objects = append(objects, replacePlaceholder(offset, &docs.Request{
+				InsertPerson: &docs.InsertPersonRequest{
+					Location:         &docs.Location{Index: offset},
+					PersonProperties: &docs.PersonProperties{Email: span.Email},
+				},
+			})...)
+		case SpanImage:
+			if span.SourceURI == "" {
+				continue
+			}
+			content = objectPlaceholder
+			objects = append(objects, replacePlaceholder(offset, &docs.Request{
+				InsertInlineImage: &docs.InsertInlineImageRequest{
+					Location: &docs.Location{Index: offset},
+					Uri:      span.SourceURI,
+				},
+			})...)
+		default:
+			continue
+		}
+
+		length := document.TextLength(content)
+		if style, fields := spanTextStyle(span); len(fields) > 0 && span.Type != SpanPerson && span.Type != SpanImage {
+			styles = append(styles, &docs.Request{
+				UpdateTextStyle: &docs.UpdateTextStyleRequest{
+					Fields: strings.Join(fields, ","),
+					Range: &docs.Range{
+						EndIndex:   offset + length,
+						StartIndex: offset,
+					},
+					TextStyle: style,
+				},
+			})
+		}
+
+		text.WriteString(content)
+		offset += length
+	}
+
+	return text.String(), append(styles, objects...)
+}
+
+// replacePlaceholder swaps the placeholder at index for a one-index object
+func replacePlaceholder(index int64, insert *docs.Request) []*docs.Request {
+	return []*docs.Request{
+		{
+			DeleteContentRange: &docs.DeleteContentRangeRequest{
+				Range: &docs.Range{
+					EndIndex:   index + 1,
+					StartIndex: index,
+				},
+			},
+		},
+		insert,
+	}
+}
+
+func spanTextStyle(span *Span) (*docs.TextStyle, []string) {
+	style := &docs.TextStyle{}
+	var fields []string
+
+	if span.Bold {
+		style.Bold = true
+		fields = append(fields, "bold")
+	}
+	if span.Italic {
+		style.Italic = true
+		fields = append(fields, "italic")
+	}
+	if span.Underline {
+		style.Underline = true
+		fields = append(fields, "underline")
+	}
+	if span.Strikethrough {
+		style.Strikethrough = true
+		fields = append(fields, "strikethrough")
+	}
+	if span.Code {
+		style.WeightedFontFamily = &docs.WeightedFontFamily{FontFamily: codeFontFamily}
+		fields = append(fields, "weightedFontFamily")
+	}
+	switch span.Baseline {
+	case "superscript":
+		style.BaselineOffset = "SUPERSCRIPT"
+		fields = append(fields, "baselineOffset")
+	case "subscript":
+		style.BaselineOffset = "SUBSCRIPT"
+		fields = append(fields, "baselineOffset")
+	}
+	if span.Link != "" {
+		if strings.HasPrefix(span.Link, "#") {
+			style.Link = &docs.Link{HeadingId: strings.TrimPrefix(span.Link, "#")}
+		} else {
+			style.Link = &docs.Link{Url: span.Link}
+		}
+		fields = append(fields, "link")
+	}
+
+	return style, fields
+}
@@ -0,0 +1,58 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"google-docs-manager/internal/ast"
+
+	"github.com/spf13/cobra"
+	"google.golang.org/api/docs/v1"
+)
+
+var (
+	applyASTCmd = &cobra.Command{
+		Args:  cobra.ExactArgs(2),
+		RunE:  runApplyAST,
+		Short: "Set document content from a JSON document model (see ast-schema)",
+		Use:   "apply-ast <document-id> <json-file>",
+	}
+
+	astSchemaCmd = &cobra.Command{
+		Args:  cobra.NoArgs,
+		RunE:  runASTSchema,
+		Short: "Print the JSON Schema of the document model used by read --format json-ast",
+		Use:   "ast-schema",
+	}
+)
+
+func runApplyAST(cmd *cobra.Command, args []string) error {
+	documentID := args[0]
+	jsonFile := args[1]
+
+	content, err := os.ReadFile(jsonFile)
+	if err != nil {
+		return fmt.Errorf("error reading JSON file: %w", err)
+	}
+
+	model := &ast.Document{}
+	if err := json.Unmarshal(content, model); err != nil {
+		return fmt.Errorf("error parsing document model: %w", err)
+	}
+
+	err = replaceDocumentContent(documentID, func(startIndex int64) ([]*docs.Request, error) {
+		return ast.ToRequests(model, startIndex)
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "%s\n", green("✅ Document content updated from document model"))
+	return nil
+}
+
+func runASTSchema(cmd *cobra.Command, args []string) error {
+	fmt.Print(string(ast.Schema))
+	return nil
+}
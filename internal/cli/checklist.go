@@ -0,0 +1,228 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"google-docs-manager/internal/conversion"
+	"google-docs-manager/internal/document"
+
+	"github.com/spf13/cobra"
+	"google.golang.org/api/docs/v1"
+)
+
+// chipPlaceholder holds the index of a person chip until it is inserted, and
+// stands for non-text elements in paragraph text
+const chipPlaceholder = "\uFFFC"
+
+var (
+	checklistCmd = &cobra.Command{
+		Short: "List and add checklist items",
+		Use:   "checklist",
+	}
+
+	checklistAddCmd = &cobra.Command{
+		Args:  cobra.MinimumNArgs(3),
+		RunE:  runChecklistAdd,
+		Short: "Add checklist items at the end of a section",
+		Use:   "add <document-id> <section> <item>...",
+	}
+
+	checklistListCmd = &cobra.Command{
+		Args:  cobra.ExactArgs(1),
+		RunE:  runChecklistList,
+		Short: "List checklist items as JSON (checked state is inferred from strikethrough)",
+		Use:   "list <document-id>",
+	}
+)
+
+// checklistItem is a checklist item of a document
+type checklistItem struct {
+	Assignee    *docs.PersonProperties `json:"assignee,omitempty"`
+	Checked     bool                   `json:"checked"`
+	EndIndex    int64                  `json:"endIndex"`
+	Level       int64                  `json:"level"`
+	SectionPath string                 `json:"sectionPath,omitempty"`
+	StartIndex  int64                  `json:"startIndex"`
+	Text        string                 `json:"text"`
+}
+
+func initChecklistCommands() {
+	checklistAddCmd.Flags().String("assignee", "", "Email of a person to mention after each item, as a person chip")
+
+	checklistListCmd.Flags().Bool("checked", false, "Only list checked items")
+	checklistListCmd.Flags().String("section", "", "Only list items in this section")
+	checklistListCmd.Flags().Bool("unchecked", false, "Only list unchecked items")
+
+	checklistCmd.AddCommand(checklistAddCmd)
+	checklistCmd.AddCommand(checklistListCmd)
+}
+
+func runChecklistList(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	documentID := args[0]
+
+	checked, _ := cmd.Flags().GetBool("checked")
+	sectionName, _ := cmd.Flags().GetString("section")
+	unchecked, _ := cmd.Flags().GetBool("unchecked")
+
+	if checked && unchecked {
+		return fmt.Errorf("--checked and --unchecked cannot be combined")
+	}
+
+	doc, err := getDocument(ctx, documentID)
+	if err != nil {
+		return err
+	}
+
+	var section *document.Section
+	if sectionName != "" {
+		section, err = document.FindSection(doc, sectionName)
+		if err != nil {
+			return err
+		}
+	}
+
+	sections := document.GetStructure(doc)
+	items := []checklistItem{}
+
+	for _, paragraph := range document.Paragraphs(doc.Body.Content) {
+		bullet := paragraph.Paragraph.Bullet
+		if bullet == nil || !conversion.IsChecklist(doc, bullet) {
+			continue
+		}
+		if section != nil && (paragraph.StartIndex < section.StartIndex || paragraph.StartIndex >= section.BodyEndIndex) {
+			continue
+		}
+
+		item := checklistItem{
+			Checked:    conversion.IsChecked(paragraph.Paragraph),
+			EndIndex:   paragraph.EndIndex,
+			Level:      bullet.NestingLevel,
+			StartIndex: paragraph.StartIndex,
+			Text:       strings.TrimSpace(strings.ReplaceAll(paragraph.Text, chipPlaceholder, "")),
+		}
+		if checked && !item.Checked || unchecked && item.Checked {
+			continue
+		}
+
+		for _, element := range paragraph.Paragraph.Elements {
+			if element.Person != nil && element.Person.PersonProperties != nil {
+				item.Assignee = element.Person.PersonProperties
+				break
+			}
+		}
+		if s := document.SectionAt(sections, paragraph.StartIndex); s != nil {
+			item.SectionPath = s.Path()
+		}
+
+		items = append(items, item)
+	}
+
+	return printJSON(items)
+}
+
+func runChecklistAdd(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	documentID := args[0]
+	sectionName := args[1]
+	items := args[2:]
+
+	assignee, _ := cmd.Flags().GetString("assignee")
+
+	// Leading tabs would be taken for nesting and removed, shifting the chips
+	for i, item := range items {
+		items[i] = strings.TrimSpace(item)
+		if items[i] == "" || strings.Contains(item, "\n") {
+			return fmt.Errorf("invalid item %q: items must be single, non-empty lines", item)
+		}
+	}
+
+	doc, err := getDocument(ctx, documentID)
+	if err != nil {
+		return err
+	}
+
+	section, err := document.FindSection(doc, sectionName)
+	if err != nil {
+		return err
+	}
+
+	// Items go after the section's own content, before its subsections
+	end := section.BodyEndIndex
+	if len(section.Children) > 0 {
+		end = section.Children[0].StartIndex
+	}
+
+	index, requests := insertionIndex(end, documentEnd(doc))
+
+	var text strings.Builder
+	var chips []int64
+	for _, item := range items {
+		text.WriteString(item)
+		if assignee != "" {
+			text.WriteString(" ")
+			chips = append(chips, index+document.TextLength(text.String()))
+			text.WriteString(chipPlaceholder)
+		}
+		text.WriteString("\n")
+	}
+	length := document.TextLength(text.String())
+
+	// Inserted paragraphs take the style of the paragraph they are inserted
+	// into, typically the next heading, so both styles are reset
+	requests = append(requests,
+		&docs.Request{
+			InsertText: &docs.InsertTextRequest{
+				Location: &docs.Location{Index: index},
+				Text:     text.String(),
+			},
+		},
+		&docs.Request{
+			UpdateParagraphStyle: &docs.UpdateParagraphStyleRequest{
+				Fields:         document.ParagraphStyleFields,
+				ParagraphStyle: &docs.ParagraphStyle{NamedStyleType: "NORMAL_TEXT"},
+				Range:          &docs.Range{EndIndex: index + length, StartIndex: index},
+			},
+		},
+		&docs.Request{
+			UpdateTextStyle: &docs.UpdateTextStyleRequest{
+				Fields:    document.TextStyleFields,
+				Range:     &docs.Range{EndIndex: index + length, StartIndex: index},
+				TextStyle: &docs.TextStyle{},
+			},
+		},
+		&docs.Request{
+			CreateParagraphBullets: &docs.CreateParagraphBulletsRequest{
+				BulletPreset: "BULLET_CHECKBOX",
+				Range:        &docs.Range{EndIndex: index + length, StartIndex: index},
+			},
+		},
+	)
+
+	// Person chips take one index, so swapping placeholders keeps indices valid
+	for _, chip := range chips {
+		requests = append(requests,
+			&docs.Request{
+				DeleteContentRange: &docs.DeleteContentRangeRequest{
+					Range: &docs.Range{EndIndex: chip + 1, StartIndex: chip},
+				},
+			},
+			&docs.Request{
+				InsertPerson: &docs.InsertPersonRequest{
+					Location:         &docs.Location{Index: chip},
+					PersonProperties: &docs.PersonProperties{Email: assignee},
+				},
+			},
+		)
+	}
+
+	if err := batchUpdate(documentID, requests); err != nil {
+		return fmt.Errorf("error adding checklist items: %w", err)
+	}
+
+	fmt.Fprintf(os.Stderr, "%s\n", green(fmt.Sprintf("✅ Added %d checklist items to '%s'", len(items), section.Title)))
+	return nil
+}
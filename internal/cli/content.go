@@ -1,10 +1,11 @@
 package cli
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"os"
-	"strconv"
+	"strings"
 
 	"google-docs-manager/internal/auth"
 	"google-docs-manager/internal/conversion"
@@ -16,10 +17,10 @@ import (
 
 var (
 	deleteTextCmd = &cobra.Command{
-		Args:  cobra.ExactArgs(3),
+		Args:  rangeArgs(0),
 		RunE:  runDeleteText,
 		Short: "Delete text in a range",
-		Use:   "delete-text <document-id> <start-index> <end-index>",
+		Use:   "delete-text <document-id> [<start-index> <end-index>]",
 	}
 
 	insertAfterCmd = &cobra.Command{
@@ -29,6 +30,13 @@ var (
 		Use:   "insert-after <document-id> <section-name> <text>",
 	}
 
+	setHTMLCmd = &cobra.Command{
+		Args:  cobra.ExactArgs(2),
+		RunE:  runSetHTML,
+		Short: "Set document content from HTML file",
+		Use:   "set-html <document-id> <html-file>",
+	}
+
 	setMarkdownCmd = &cobra.Command{
 		Args:  cobra.ExactArgs(2),
 		RunE:  runSetMarkdown,
@@ -44,18 +52,21 @@ var (
 	}
 )
 
+func initContentCommands() {
+	addRangeFlags(deleteTextCmd)
+
+	updateSectionCmd.Flags().Bool("include-heading", false, "Replace the heading as well; the markdown must then start with the new heading")
+	updateSectionCmd.Flags().Bool("keep-subsections", false, "Only update the content before the first subsection")
+	updateSectionCmd.Flags().String("mode", "replace", "Update mode (replace, append, prepend)")
+}
+
 func runDeleteText(cmd *cobra.Command, args []string) error {
 	ctx := context.Background()
 	documentID := args[0]
 
-	startIndex, err := strconv.ParseInt(args[1], 10, 64)
-	if err != nil {
-		return fmt.Errorf("invalid start index: %w", err)
-	}
-
-	endIndex, err := strconv.ParseInt(args[2], 10, 64)
+	ranges, err := commandRanges(ctx, cmd, documentID, args[1:])
 	if err != nil {
-		return fmt.Errorf("invalid end index: %w", err)
+		return err
 	}
 
 	service, err := auth.GetDocsService(ctx)
@@ -63,16 +74,13 @@ func runDeleteText(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	requests := []*docs.Request{
-		{
+	requests := rangeRequests(ranges, func(r *docs.Range) *docs.Request {
+		return &docs.Request{
 			DeleteContentRange: &docs.DeleteContentRangeRequest{
-				Range: &docs.Range{
-					EndIndex:   endIndex,
-					StartIndex: startIndex,
-				},
+				Range: r,
 			},
-		},
-	}
+		}
+	})
 
 	_, err = service.Documents.BatchUpdate(documentID, &docs.BatchUpdateDocumentRequest{
 		Requests: requests,
@@ -82,7 +90,11 @@ func runDeleteText(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("error deleting text: %w", err)
 	}
 
-	fmt.Fprintf(os.Stderr, "%s\n", green(fmt.Sprintf("✅ Text deleted from %d to %d", startIndex, endIndex)))
+	if len(ranges) == 1 {
+		fmt.Fprintf(os.Stderr, "%s\n", green(fmt.Sprintf("✅ Text deleted from %d to %d", ranges[0].StartIndex, ranges[0].EndIndex)))
+	} else {
+		fmt.Fprintf(os.Stderr, "%s\n", green(fmt.Sprintf("✅ Text deleted in %d ranges", len(ranges))))
+	}
 	return nil
 }
 
@@ -102,9 +114,9 @@ func runInsertAfter(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("error getting document: %w", err)
 	}
 
-	section := document.FindSection(doc, sectionName)
-	if section == nil {
-		return fmt.Errorf("section not found: %s", sectionName)
+	section, err := document.FindSection(doc, sectionName)
+	if err != nil {
+		return err
 	}
 
 	requests := []*docs.Request{
@@ -128,8 +140,33 @@ func runInsertAfter(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+func runSetHTML(cmd *cobra.Command, args []string) error {
+	documentID := args[0]
+	htmlFile := args[1]
+
+	content, err := os.ReadFile(htmlFile)
+	if err != nil {
+		return fmt.Errorf("error reading HTML file: %w", err)
+	}
+
+	build := func(startIndex int64) ([]*docs.Request, error) {
+		presets, err := loadPresets()
+		if err != nil {
+			return nil, err
+		}
+
+		return conversion.HTMLToDocsRequests(bytes.NewReader(content), startIndex, conversion.MarkdownOptions{Presets: presets})
+	}
+
+	if err := replaceDocumentContent(documentID, build); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "%s\n", green("✅ Document content updated from HTML"))
+	return nil
+}
+
 func runSetMarkdown(cmd *cobra.Command, args []string) error {
-	ctx := context.Background()
 	documentID := args[0]
 	markdownFile := args[1]
 
@@ -138,6 +175,32 @@ func runSetMarkdown(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("error reading markdown file: %w", err)
 	}
 
+	if err := replaceDocumentContent(documentID, markdownBuilder(string(content))); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "%s\n", green("✅ Document content updated from markdown"))
+	return nil
+}
+
+// contentBuilder returns the requests inserting new content at startIndex
+type contentBuilder func(startIndex int64) ([]*docs.Request, error)
+
+func markdownBuilder(markdown string) contentBuilder {
+	return func(startIndex int64) ([]*docs.Request, error) {
+		presets, err := loadPresets()
+		if err != nil {
+			return nil, err
+		}
+
+		return conversion.MarkdownToDocsRequestsWithOptions(markdown, startIndex, conversion.MarkdownOptions{Presets: presets}), nil
+	}
+}
+
+// replaceDocumentContent replaces the whole document body with the built content
+func replaceDocumentContent(documentID string, build contentBuilder) error {
+	ctx := context.Background()
+
 	service, err := auth.GetDocsService(ctx)
 	if err != nil {
 		return err
@@ -162,8 +225,11 @@ func runSetMarkdown(cmd *cobra.Command, args []string) error {
 		})
 	}
 
-	markdownRequests := conversion.MarkdownToDocsRequests(string(content), 1)
-	requests = append(requests, markdownRequests...)
+	contentRequests, err := build(1)
+	if err != nil {
+		return err
+	}
+	requests = append(requests, contentRequests...)
 
 	_, err = service.Documents.BatchUpdate(documentID, &docs.BatchUpdateDocumentRequest{
 		Requests: requests,
@@ -173,7 +239,6 @@ func runSetMarkdown(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("error updating document: %w", err)
 	}
 
-	fmt.Fprintf(os.Stderr, "%s\n", green("✅ Document content updated from markdown"))
 	return nil
 }
 
@@ -183,6 +248,18 @@ func runUpdateSection(cmd *cobra.Command, args []string) error {
 	sectionName := args[1]
 	markdownFile := args[2]
 
+	mode, _ := cmd.Flags().GetString("mode")
+	includeHeading, _ := cmd.Flags().GetBool("include-heading")
+	keepSubsections, _ := cmd.Flags().GetBool("keep-subsections")
+
+	mode = strings.ToLower(mode)
+	if mode != "replace" && mode != "append" && mode != "prepend" {
+		return fmt.Errorf("invalid mode: %s (must be replace, append, or prepend)", mode)
+	}
+	if includeHeading && mode != "replace" {
+		return fmt.Errorf("--include-heading can only be used with --mode replace")
+	}
+
 	content, err := os.ReadFile(markdownFile)
 	if err != nil {
 		return fmt.Errorf("error reading markdown file: %w", err)
@@ -198,23 +275,45 @@ func runUpdateSection(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("error getting document: %w", err)
 	}
 
-	section := document.FindSection(doc, sectionName)
-	if section == nil {
-		return fmt.Errorf("section not found: %s", sectionName)
+	section, err := document.FindSection(doc, sectionName)
+	if err != nil {
+		return err
 	}
 
-	requests := []*docs.Request{
-		{
-			DeleteContentRange: &docs.DeleteContentRangeRequest{
-				Range: &docs.Range{
-					EndIndex:   section.EndIndex + 1,
-					StartIndex: section.EndIndex,
-				},
-			},
-		},
+	// The region being updated runs from the end of the heading to the next
+	// heading of the same or higher level, or to the first subsection when
+	// subsections are kept
+	startIndex := section.BodyStartIndex
+	if includeHeading {
+		startIndex = section.StartIndex
+	}
+	endIndex := section.BodyEndIndex
+	if keepSubsections && len(section.Children) > 0 {
+		endIndex = section.Children[0].StartIndex
+	}
+
+	var requests []*docs.Request
+	insertIndex := startIndex
+
+	switch mode {
+	case "replace":
+		// The final newline of the document cannot be deleted
+		if min(endIndex, documentEnd(doc)-1) > startIndex {
+			requests = append(requests, deleteRangeRequest(startIndex, endIndex, documentEnd(doc)))
+		}
+	case "append":
+		insertIndex = endIndex
+	}
+
+	insertIndex, openRequests := insertionIndex(insertIndex, documentEnd(doc))
+	requests = append(requests, openRequests...)
+
+	presets, err := loadPresets()
+	if err != nil {
+		return err
 	}
 
-	markdownRequests := conversion.MarkdownToDocsRequests(string(content), section.EndIndex)
+	markdownRequests := conversion.MarkdownToDocsRequestsWithOptions(strings.TrimRight(string(content), "\n"), insertIndex, conversion.MarkdownOptions{Presets: presets})
 	requests = append(requests, markdownRequests...)
 
 	_, err = service.Documents.BatchUpdate(documentID, &docs.BatchUpdateDocumentRequest{
@@ -0,0 +1,100 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"google-docs-manager/internal/document"
+
+	"github.com/spf13/cobra"
+	"google.golang.org/api/docs/v1"
+)
+
+var copyStyleCmd = &cobra.Command{
+	Args:  cobra.ExactArgs(1),
+	RunE:  runCopyStyle,
+	Short: "Copy the text and paragraph style of one range to others",
+	Use:   "copy-style <document-id>",
+}
+
+func initCopyStyleCommands() {
+	copyStyleCmd.Flags().String("from", "", "Selector of the range to copy the style of, e.g. section=Intro,paragraph=2 or match=Note")
+	copyStyleCmd.Flags().String("from-document", "", "Copy the style from this document instead of the target document")
+	copyStyleCmd.Flags().Bool("paragraph-only", false, "Only copy the paragraph style")
+	copyStyleCmd.Flags().Bool("text-only", false, "Only copy the text style")
+	copyStyleCmd.Flags().String("to", "", "Selector of the ranges to apply the style to, e.g. match=TODO,occurrence=all")
+	_ = copyStyleCmd.MarkFlagRequired("from")
+	_ = copyStyleCmd.MarkFlagRequired("to")
+}
+
+func runCopyStyle(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	documentID := args[0]
+
+	from, _ := cmd.Flags().GetString("from")
+	fromDocument, _ := cmd.Flags().GetString("from-document")
+	paragraphOnly, _ := cmd.Flags().GetBool("paragraph-only")
+	textOnly, _ := cmd.Flags().GetBool("text-only")
+	to, _ := cmd.Flags().GetString("to")
+
+	if paragraphOnly && textOnly {
+		return fmt.Errorf("--paragraph-only and --text-only cannot be combined")
+	}
+
+	fromSelector, err := document.ParseRangeSelector(from)
+	if err != nil {
+		return err
+	}
+
+	toSelector, err := document.ParseRangeSelector(to)
+	if err != nil {
+		return err
+	}
+
+	doc, err := getDocument(ctx, documentID)
+	if err != nil {
+		return err
+	}
+
+	source := doc
+	if fromDocument != "" {
+		source, err = getDocument(ctx, fromDocument)
+		if err != nil {
+			return err
+		}
+	}
+
+	sourceRanges, err := document.ResolveRanges(source, fromSelector)
+	if err != nil {
+		return fmt.Errorf("error resolving --from: %w", err)
+	}
+
+	// The style is read where the source range starts
+	textStyle, paragraphStyle, err := document.StyleAt(source.Body.Content, sourceRanges[0].StartIndex)
+	if err != nil {
+		return err
+	}
+
+	targetRanges, err := document.ResolveRanges(doc, toSelector)
+	if err != nil {
+		return fmt.Errorf("error resolving --to: %w", err)
+	}
+
+	if paragraphOnly {
+		textStyle = nil
+	}
+	if textOnly {
+		paragraphStyle = nil
+	} else if paragraphStyle == nil {
+		paragraphStyle = &docs.ParagraphStyle{}
+	}
+
+	requests := document.CopyStyleRequests(textStyle, paragraphStyle, targetRanges)
+	if err := batchUpdate(documentID, requests); err != nil {
+		return fmt.Errorf("error copying style: %w", err)
+	}
+
+	fmt.Fprintf(os.Stderr, "%s\n", green(fmt.Sprintf("✅ Style copied to %d ranges", len(targetRanges))))
+	return nil
+}
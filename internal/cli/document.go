@@ -5,7 +5,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
 
+	"google-docs-manager/internal/assets"
+	"google-docs-manager/internal/ast"
 	"google-docs-manager/internal/auth"
 	"google-docs-manager/internal/conversion"
 	"google-docs-manager/internal/document"
@@ -47,7 +51,7 @@ var (
 	readCmd = &cobra.Command{
 		Args:  cobra.ExactArgs(1),
 		RunE:  runRead,
-		Short: "Read a document and output as markdown",
+		Short: "Read a document and output as markdown, HTML or another text format",
 		Use:   "read <document-id>",
 	}
 )
@@ -55,6 +59,12 @@ var (
 func initDocumentCommands() {
 	copyCmd.Flags().String("folder", "", "Folder ID to place the copied document in")
 	createCmd.Flags().String("folder", "", "Folder ID to create document in")
+	getStructureCmd.Flags().Bool("tree", false, "Output sections as a tree with nested subsections")
+	readCmd.Flags().String("assets-dir", "", "Directory to download inline and positioned images into")
+	readCmd.Flags().String("format", "markdown", "Output format (markdown, html, asciidoc, rst, latex, org, confluence, jira, json-ast)")
+	readCmd.Flags().Bool("comments", false, "Include open Drive comments: CriticMarkup annotations with --format markdown, <mark> highlights with --format html; other formats leave them out")
+	readCmd.Flags().StringP("output", "o", "", "Write the output to this file instead of standard output; image links are relative to its directory")
+	readCmd.Flags().String("suggestions", "", "Suggestions view mode (DEFAULT_FOR_CURRENT_ACCESS, SUGGESTIONS_INLINE, PREVIEW_SUGGESTIONS_ACCEPTED, PREVIEW_WITHOUT_SUGGESTIONS)")
 }
 
 func runCopy(cmd *cobra.Command, args []string) error {
@@ -92,38 +102,45 @@ func runCreate(cmd *cobra.Command, args []string) error {
 	ctx := context.Background()
 	title := args[0]
 
-	service, err := auth.GetDocsService(ctx)
+	folderID, _ := cmd.Flags().GetString("folder")
+
+	result, err := createDocument(ctx, title, folderID)
 	if err != nil {
 		return err
 	}
 
-	doc := &docs.Document{
-		Title: title,
+	fmt.Fprintf(os.Stderr, "%s\n", green("✅ Document created: "+result.Title))
+	fmt.Fprintf(os.Stderr, "%s\n", green("   ID: "+result.DocumentId))
+	fmt.Println(result.DocumentId)
+
+	return nil
+}
+
+// createDocument creates an empty document, in a folder when folderID is set
+func createDocument(ctx context.Context, title, folderID string) (*docs.Document, error) {
+	service, err := auth.GetDocsService(ctx)
+	if err != nil {
+		return nil, err
 	}
 
-	result, err := service.Documents.Create(doc).Do()
+	result, err := service.Documents.Create(&docs.Document{Title: title}).Do()
 	if err != nil {
-		return fmt.Errorf("error creating document: %w", err)
+		return nil, fmt.Errorf("error creating document: %w", err)
 	}
 
-	folderID, _ := cmd.Flags().GetString("folder")
 	if folderID != "" {
 		driveService, err := auth.GetDriveService(ctx)
 		if err != nil {
-			return err
+			return nil, err
 		}
 
 		_, err = driveService.Files.Update(result.DocumentId, &drive.File{}).AddParents(folderID).Do()
 		if err != nil {
-			return fmt.Errorf("error moving to folder: %w", err)
+			return nil, fmt.Errorf("error moving to folder: %w", err)
 		}
 	}
 
-	fmt.Fprintf(os.Stderr, "%s\n", green("✅ Document created: "+result.Title))
-	fmt.Fprintf(os.Stderr, "%s\n", green("   ID: "+result.DocumentId))
-	fmt.Println(result.DocumentId)
-
-	return nil
+	return result, nil
 }
 
 func runGetStructure(cmd *cobra.Command, args []string) error {
@@ -140,6 +157,11 @@ func runGetStructure(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("error getting document: %w", err)
 	}
 
+	tree, _ := cmd.Flags().GetBool("tree")
+	if tree {
+		return printJSON(document.GetSectionTree(doc))
+	}
+
 	sections := document.GetStructure(doc)
 	return printJSON(sections)
 }
@@ -177,17 +199,130 @@ func runRead(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	doc, err := service.Documents.Get(documentID).Do()
+	call := service.Documents.Get(documentID)
+
+	suggestionsMode, _ := cmd.Flags().GetString("suggestions")
+	if suggestionsMode != "" {
+		suggestionsMode = strings.ToUpper(suggestionsMode)
+		validModes := map[string]bool{
+			"DEFAULT_FOR_CURRENT_ACCESS":   true,
+			"PREVIEW_SUGGESTIONS_ACCEPTED": true,
+			"PREVIEW_WITHOUT_SUGGESTIONS":  true,
+			"SUGGESTIONS_INLINE":           true,
+		}
+
+		if !validModes[suggestionsMode] {
+			return fmt.Errorf("invalid suggestions view mode: %s (must be DEFAULT_FOR_CURRENT_ACCESS, SUGGESTIONS_INLINE, PREVIEW_SUGGESTIONS_ACCEPTED, or PREVIEW_WITHOUT_SUGGESTIONS)", suggestionsMode)
+		}
+		call = call.SuggestionsViewMode(suggestionsMode)
+	}
+
+	doc, err := call.Do()
 	if err != nil {
 		return fmt.Errorf("error reading document: %w", err)
 	}
 
-	markdown := conversion.DocsToMarkdown(doc)
-	fmt.Println(markdown)
+	opts := conversion.RenderOptions{}
+
+	withComments, _ := cmd.Flags().GetBool("comments")
+	if withComments {
+		comments, err := fetchComments(ctx, documentID)
+		if err != nil {
+			return err
+		}
+		opts.Comments = comments
+	}
+
+	output, _ := cmd.Flags().GetString("output")
 
+	assetsDir, _ := cmd.Flags().GetString("assets-dir")
+	if assetsDir != "" {
+		client, err := auth.GetClient(ctx)
+		if err != nil {
+			return err
+		}
+
+		// Image links are relative to the file referencing them
+		base := "."
+		if output != "" {
+			base = filepath.Dir(output)
+		}
+
+		result, err := assets.Export(ctx, client, doc, assetsDir, base)
+		if err != nil {
+			return err
+		}
+
+		for _, failure := range result.Failures {
+			fmt.Fprintf(os.Stderr, "%s\n", red("⚠️  Could not export object "+failure.ObjectID+": "+failure.Reason))
+		}
+		opts.Assets = result.Paths
+	}
+
+	format, _ := cmd.Flags().GetString("format")
+
+	var content string
+	if strings.EqualFold(format, "json-ast") {
+		data, err := json.MarshalIndent(ast.FromDocument(doc), "", "  ")
+		if err != nil {
+			return err
+		}
+		content = string(data)
+	} else {
+		renderer, err := conversion.NewRenderer(format, opts)
+		if err != nil {
+			return err
+		}
+		content = strings.TrimRight(conversion.Render(doc, renderer, opts), "\n")
+	}
+
+	if output == "" {
+		fmt.Println(content)
+		return nil
+	}
+
+	if err := os.WriteFile(output, []byte(content+"\n"), exportFilePerm); err != nil {
+		return fmt.Errorf("error writing output: %w", err)
+	}
+
+	fmt.Fprintf(os.Stderr, "%s\n", green("✅ Document written to "+output))
 	return nil
 }
 
+// fetchComments lists the unresolved comments of a document through the Drive API
+func fetchComments(ctx context.Context, documentID string) ([]conversion.Comment, error) {
+	driveService, err := auth.GetDriveService(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var comments []conversion.Comment
+	err = driveService.Comments.List(documentID).
+		Fields("nextPageToken", "comments(author/displayName,content,deleted,quotedFileContent/value,resolved)").
+		Pages(ctx, func(list *drive.CommentList) error {
+			for _, comment := range list.Comments {
+				if comment.Deleted || comment.Resolved {
+					continue
+				}
+
+				c := conversion.Comment{Content: comment.Content}
+				if comment.Author != nil {
+					c.Author = comment.Author.DisplayName
+				}
+				if comment.QuotedFileContent != nil {
+					c.Quoted = comment.QuotedFileContent.Value
+				}
+				comments = append(comments, c)
+			}
+			return nil
+		})
+	if err != nil {
+		return nil, fmt.Errorf("error listing comments: %w", err)
+	}
+
+	return comments, nil
+}
+
 func printJSON(v interface{}) error {
 	data, err := json.MarshalIndent(v, "", "  ")
 	if err != nil {
@@ -0,0 +1,192 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"google-docs-manager/internal/auth"
+
+	"github.com/spf13/cobra"
+	"google.golang.org/api/drive/v3"
+	"google.golang.org/api/googleapi"
+)
+
+const (
+	exportDirPerm  = 0755
+	exportFilePerm = 0644
+	docsMimeType   = "application/vnd.google-apps.document"
+)
+
+var exportFormats = map[string]string{
+	"docx": "application/vnd.openxmlformats-officedocument.wordprocessingml.document",
+	"epub": "application/epub+zip",
+	"odt":  "application/vnd.oasis.opendocument.text",
+	"pdf":  "application/pdf",
+	"rtf":  "application/rtf",
+	"txt":  "text/plain",
+	"zip":  "application/zip",
+}
+
+var unsafeFileChars = regexp.MustCompile(`[/\\:*?"<>|\x00-\x1f]+`)
+
+var exportCmd = &cobra.Command{
+	Args:  cobra.MaximumNArgs(1),
+	RunE:  runExport,
+	Short: "Export a document (or every document in a folder) to an office format",
+	Use:   "export [document-id]",
+}
+
+func initExportCommands() {
+	exportCmd.Flags().String("folder", "", "Export every Google Doc in this folder instead of a single document")
+	exportCmd.Flags().String("format", "pdf", "Export format (pdf, docx, odt, epub, rtf, txt, zip)")
+	exportCmd.Flags().StringP("output", "o", "", "Output file (single document) or directory (folder export)")
+}
+
+func runExport(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	format, _ := cmd.Flags().GetString("format")
+	format = strings.ToLower(format)
+	mimeType, ok := exportFormats[format]
+	if !ok {
+		return fmt.Errorf("invalid format: %s (must be pdf, docx, odt, epub, rtf, txt, or zip)", format)
+	}
+
+	folderID, _ := cmd.Flags().GetString("folder")
+	output, _ := cmd.Flags().GetString("output")
+
+	if (folderID == "") == (len(args) == 0) {
+		return fmt.Errorf("specify either a document ID or --folder")
+	}
+
+	driveService, err := auth.GetDriveService(ctx)
+	if err != nil {
+		return err
+	}
+
+	if folderID != "" {
+		return exportFolder(ctx, driveService, folderID, format, mimeType, output)
+	}
+
+	documentID := args[0]
+	if output == "" {
+		file, err := driveService.Files.Get(documentID).Fields("name").Do()
+		if err != nil {
+			return fmt.Errorf("error getting document: %w", err)
+		}
+		output = exportFileName(file.Name, format)
+	}
+
+	if err := exportDocument(driveService, documentID, mimeType, output); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "%s\n", green("✅ Document exported to "+output))
+	fmt.Println(output)
+	return nil
+}
+
+func exportFolder(ctx context.Context, driveService *drive.Service, folderID string, format string, mimeType string, outputDir string) error {
+	if outputDir == "" {
+		outputDir = "."
+	}
+
+	if err := os.MkdirAll(outputDir, exportDirPerm); err != nil {
+		return fmt.Errorf("error creating output directory: %w", err)
+	}
+
+	query := fmt.Sprintf("'%s' in parents and mimeType = '%s' and trashed = false", folderID, docsMimeType)
+
+	var files []*drive.File
+	err := driveService.Files.List().
+		Q(query).
+		Fields("nextPageToken", "files(id,name)").
+		Pages(ctx, func(list *drive.FileList) error {
+			files = append(files, list.Files...)
+			return nil
+		})
+	if err != nil {
+		return fmt.Errorf("error listing folder: %w", err)
+	}
+
+	if len(files) == 0 {
+		return fmt.Errorf("no Google Docs found in folder %s", folderID)
+	}
+
+	used := map[string]int{}
+	failed := 0
+
+	for _, file := range files {
+		name := exportFileName(file.Name, format)
+		used[name]++
+		if used[name] > 1 {
+			name = exportFileName(fmt.Sprintf("%s (%d)", file.Name, used[name]), format)
+		}
+
+		path := filepath.Join(outputDir, name)
+		if err := exportDocument(driveService, file.Id, mimeType, path); err != nil {
+			fmt.Fprintf(os.Stderr, "%s\n", red("❌ "+file.Name+": "+err.Error()))
+			failed++
+			continue
+		}
+
+		fmt.Fprintf(os.Stderr, "%s\n", green("✅ "+file.Name+" → "+path))
+		fmt.Println(path)
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d documents could not be exported", failed, len(files))
+	}
+	return nil
+}
+
+func exportDocument(driveService *drive.Service, documentID string, mimeType string, path string) error {
+	resp, err := driveService.Files.Export(documentID, mimeType).Download()
+	if err != nil {
+		if isExportSizeLimitError(err) {
+			return fmt.Errorf("document %s is too large to export: Drive limits exports to 10MB, try a lighter format such as txt or docx", documentID)
+		}
+		return fmt.Errorf("error exporting document: %w", err)
+	}
+	defer resp.Body.Close()
+
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, exportFilePerm)
+	if err != nil {
+		return fmt.Errorf("error creating output file: %w", err)
+	}
+
+	if _, err := io.Copy(f, resp.Body); err != nil {
+		f.Close()
+		return fmt.Errorf("error writing output file: %w", err)
+	}
+
+	return f.Close()
+}
+
+func isExportSizeLimitError(err error) bool {
+	var apiErr *googleapi.Error
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+
+	for _, item := range apiErr.Errors {
+		if item.Reason == "exportSizeLimitExceeded" {
+			return true
+		}
+	}
+	return strings.Contains(strings.ToLower(apiErr.Message), "too large to be exported")
+}
+
+func exportFileName(title string, format string) string {
+	name := strings.TrimSpace(unsafeFileChars.ReplaceAllString(title, "_"))
+	if name == "" {
+		name = "document"
+	}
+	return name + "." + format
+}
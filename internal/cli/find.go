@@ -0,0 +1,158 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+
+	"google-docs-manager/internal/document"
+
+	"github.com/spf13/cobra"
+)
+
+// findScopes lists the parts of a document find can search
+var findScopes = []string{"body", "tables", "headers", "footers", "footnotes"}
+
+var findCmd = &cobra.Command{
+	Args:  cobra.ExactArgs(2),
+	RunE:  runFind,
+	Short: "Find text and output the hits as JSON",
+	Use:   "find <document-id> <query>",
+}
+
+// findHit is a match of a find query
+type findHit struct {
+	After          string `json:"after"`
+	Before         string `json:"before"`
+	EndIndex       int64  `json:"endIndex"`
+	InTable        bool   `json:"inTable,omitempty"`
+	ParagraphStyle string `json:"paragraphStyle"`
+	SectionPath    string `json:"sectionPath,omitempty"`
+	Segment        string `json:"segment"`
+	SegmentID      string `json:"segmentId,omitempty"`
+	StartIndex     int64  `json:"startIndex"`
+	Text           string `json:"text"`
+}
+
+func initFindCommands() {
+	findCmd.Flags().Bool("case-sensitive", false, "Match case")
+	findCmd.Flags().Int("context", 40, "Characters of surrounding text to include before and after each hit")
+	findCmd.Flags().StringSlice("in", []string{"body", "tables"}, "Parts to search: "+strings.Join(findScopes, ", ")+", or all (tables are those of the body)")
+	findCmd.Flags().Bool("regex", false, "Treat the query as a regular expression")
+	findCmd.Flags().Bool("whole-word", false, "Only match whole words")
+}
+
+func runFind(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	documentID := args[0]
+	query := args[1]
+
+	caseSensitive, _ := cmd.Flags().GetBool("case-sensitive")
+	contextWidth, _ := cmd.Flags().GetInt("context")
+	in, _ := cmd.Flags().GetStringSlice("in")
+	regexMode, _ := cmd.Flags().GetBool("regex")
+	wholeWord, _ := cmd.Flags().GetBool("whole-word")
+
+	scopes := map[string]bool{}
+	for _, scope := range in {
+		scope = strings.ToLower(strings.TrimSpace(scope))
+		if scope == "all" {
+			for _, s := range findScopes {
+				scopes[s] = true
+			}
+			continue
+		}
+
+		if !slices.Contains(findScopes, scope) {
+			return fmt.Errorf("invalid search scope: %s (must be %s, or all)", scope, strings.Join(findScopes, ", "))
+		}
+		scopes[scope] = true
+	}
+
+	if query == "" {
+		return fmt.Errorf("query must not be empty")
+	}
+
+	expr := query
+	if !regexMode {
+		expr = regexp.QuoteMeta(query)
+	}
+	if wholeWord {
+		expr = `\b(?:` + expr + `)\b`
+	}
+	if !caseSensitive {
+		expr = "(?i)" + expr
+	}
+
+	pattern, err := regexp.Compile(expr)
+	if err != nil {
+		return fmt.Errorf("invalid regex: %w", err)
+	}
+
+	doc, err := getDocument(ctx, documentID)
+	if err != nil {
+		return err
+	}
+
+	sections := document.GetStructure(doc)
+	hits := []findHit{}
+
+	addHits := func(segment, segmentID string, paragraphs []*document.TextParagraph) {
+		for _, match := range document.FindMatches(paragraphs, pattern) {
+			paragraph := match.Paragraph
+			// The body and tables scopes split the body; headers, footers and
+			// footnotes are searched whole, tables included
+			if segment == "body" && (paragraph.InTable && !scopes["tables"] || !paragraph.InTable && !scopes["body"]) {
+				continue
+			}
+
+			start, end := match.Groups[0], match.Groups[1]
+			before, after := paragraph.Context(start, end, contextWidth)
+			r := match.Range()
+
+			hit := findHit{
+				After:      after,
+				Before:     before,
+				EndIndex:   r.EndIndex,
+				InTable:    paragraph.InTable,
+				Segment:    segment,
+				SegmentID:  segmentID,
+				StartIndex: r.StartIndex,
+				Text:       paragraph.Text[start:end],
+			}
+			if paragraph.Paragraph.ParagraphStyle != nil {
+				hit.ParagraphStyle = paragraph.Paragraph.ParagraphStyle.NamedStyleType
+			}
+			if segment == "body" {
+				if section := document.SectionAt(sections, r.StartIndex); section != nil {
+					hit.SectionPath = section.Path()
+				}
+			}
+
+			hits = append(hits, hit)
+		}
+	}
+
+	addHits("body", "", document.Paragraphs(doc.Body.Content))
+
+	if scopes["headers"] {
+		for _, id := range slices.Sorted(maps.Keys(doc.Headers)) {
+			addHits("header", id, document.Paragraphs(doc.Headers[id].Content))
+		}
+	}
+	if scopes["footers"] {
+		for _, id := range slices.Sorted(maps.Keys(doc.Footers)) {
+			addHits("footer", id, document.Paragraphs(doc.Footers[id].Content))
+		}
+	}
+	if scopes["footnotes"] {
+		for _, id := range slices.Sorted(maps.Keys(doc.Footnotes)) {
+			addHits("footnote", id, document.Paragraphs(doc.Footnotes[id].Content))
+		}
+	}
+
+	return printJSON(hits)
+}
@@ -4,85 +4,110 @@ import (
 	"context"
 	"fmt"
 	"os"
-	"strconv"
+	"slices"
 	"strings"
 
 	"google-docs-manager/internal/auth"
 	"google-docs-manager/internal/conversion"
+	"google-docs-manager/internal/document"
 
 	"github.com/spf13/cobra"
 	"google.golang.org/api/docs/v1"
 )
 
+const (
+	defaultBulletPreset   = "BULLET_DISC_CIRCLE_SQUARE"
+	defaultNumberedPreset = "NUMBERED_DECIMAL_ALPHA_ROMAN"
+)
+
 var (
 	alignParagraphCmd = &cobra.Command{
-		Args:  cobra.ExactArgs(4),
+		Args:  rangeArgs(1),
 		RunE:  runAlignParagraph,
 		Short: "Align paragraph (START, CENTER, END, JUSTIFIED)",
-		Use:   "align-paragraph <document-id> <start-index> <end-index> <alignment>",
+		Use:   "align-paragraph <document-id> [<start-index> <end-index>] <alignment>",
+	}
+
+	convertListCmd = &cobra.Command{
+		Args:  rangeArgs(0),
+		RunE:  runConvertList,
+		Short: "Convert lists between bullets and numbering, keeping their nesting",
+		Use:   "convert-list <document-id> [<start-index> <end-index>]",
 	}
 
 	createBulletsCmd = &cobra.Command{
-		Args:  cobra.ExactArgs(3),
+		Args:  rangeArgs(0),
 		RunE:  runCreateBullets,
 		Short: "Create bulleted list",
-		Use:   "create-bullets <document-id> <start-index> <end-index>",
+		Use:   "create-bullets <document-id> [<start-index> <end-index>]",
 	}
 
 	createNumberedCmd = &cobra.Command{
-		Args:  cobra.ExactArgs(3),
+		Args:  rangeArgs(0),
 		RunE:  runCreateNumbered,
 		Short: "Create numbered list",
-		Use:   "create-numbered <document-id> <start-index> <end-index>",
+		Use:   "create-numbered <document-id> [<start-index> <end-index>]",
+	}
+
+	formatParagraphCmd = &cobra.Command{
+		Args:  rangeArgs(0),
+		RunE:  runFormatParagraph,
+		Short: "Format paragraphs (named style, spacing, indents, borders, shading, ...)",
+		Use:   "format-paragraph <document-id> [<start-index> <end-index>]",
 	}
 
 	formatTextCmd = &cobra.Command{
-		Args:  cobra.ExactArgs(3),
+		Args:  rangeArgs(0),
 		RunE:  runFormatText,
-		Short: "Format text (bold, italic, underline, color, size)",
-		Use:   "format-text <document-id> <start-index> <end-index>",
+		Short: "Format text (font, weight, size, colors, bold, italic, links, ...)",
+		Use:   "format-text <document-id> [<start-index> <end-index>]",
 	}
 
 	removeBulletsCmd = &cobra.Command{
-		Args:  cobra.ExactArgs(3),
+		Args:  rangeArgs(0),
 		RunE:  runRemoveBullets,
 		Short: "Remove bullets/numbering from list",
-		Use:   "remove-bullets <document-id> <start-index> <end-index>",
+		Use:   "remove-bullets <document-id> [<start-index> <end-index>]",
 	}
 )
 
 func initFormattingCommands() {
-	formatTextCmd.Flags().Bool("bold", false, "Make text bold")
-	formatTextCmd.Flags().Bool("italic", false, "Make text italic")
-	formatTextCmd.Flags().Bool("underline", false, "Underline text")
-	formatTextCmd.Flags().String("color", "", "Text color (hex, e.g., #FF0000)")
-	formatTextCmd.Flags().Float64("size", 0, "Font size in points")
+	addRangeFlags(alignParagraphCmd)
+	addRangeFlags(convertListCmd)
+	addRangeFlags(createBulletsCmd)
+	addRangeFlags(createNumberedCmd)
+	addRangeFlags(formatParagraphCmd)
+	addRangeFlags(formatTextCmd)
+	addRangeFlags(removeBulletsCmd)
+
+	addParagraphStyleFlags(formatParagraphCmd)
+	addTextStyleFlags(formatTextCmd)
+
+	for _, cmd := range []*cobra.Command{createBulletsCmd, createNumberedCmd} {
+		cmd.Flags().Int64("level", 0, "Nest the items this many levels deeper (leading tabs in the text nest items as well)")
+		cmd.Flags().String("preset", "", "Bullet preset: "+strings.Join(document.BulletPresets, ", "))
+	}
+	for _, cmd := range []*cobra.Command{convertListCmd, createNumberedCmd} {
+		cmd.Flags().Bool("continue", false, "Continue the numbering of the closest list before the items")
+		cmd.Flags().Bool("restart", false, "Restart numbering instead of joining a list right before the items")
+	}
+	convertListCmd.Flags().String("to", "", "Convert to bullet, numbered, or a bullet preset")
+	_ = convertListCmd.MarkFlagRequired("to")
+	formatTextCmd.Flags().String("preset", "", "Apply a text and paragraph style preset from ~/.config/google-docs-manager/"+presetsFile)
 }
 
 func runAlignParagraph(cmd *cobra.Command, args []string) error {
 	ctx := context.Background()
 	documentID := args[0]
+	alignment := strings.ToUpper(args[len(args)-1])
 
-	startIndex, err := strconv.ParseInt(args[1], 10, 64)
-	if err != nil {
-		return fmt.Errorf("invalid start index: %w", err)
+	if !slices.Contains(validAlignments, alignment) {
+		return fmt.Errorf("invalid alignment: %s (must be START, CENTER, END, or JUSTIFIED)", alignment)
 	}
 
-	endIndex, err := strconv.ParseInt(args[2], 10, 64)
+	ranges, err := commandRanges(ctx, cmd, documentID, args[1:len(args)-1])
 	if err != nil {
-		return fmt.Errorf("invalid end index: %w", err)
-	}
-
-	alignment := strings.ToUpper(args[3])
-	validAlignments := map[string]bool{
-		"CENTER":    true,
-		"END":       true,
-		"JUSTIFIED": true,
-		"START":     true,
-	}
-
-	if !validAlignments[alignment] {
-		return fmt.Errorf("invalid alignment: %s (must be START, CENTER, END, or JUSTIFIED)", alignment)
+		return err
 	}
 
 	service, err := auth.GetDocsService(ctx)
@@ -90,20 +115,17 @@ func runAlignParagraph(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	requests := []*docs.Request{
-		{
+	requests := rangeRequests(ranges, func(r *docs.Range) *docs.Request {
+		return &docs.Request{
 			UpdateParagraphStyle: &docs.UpdateParagraphStyleRequest{
 				Fields: "alignment",
 				ParagraphStyle: &docs.ParagraphStyle{
 					Alignment: alignment,
 				},
-				Range: &docs.Range{
-					EndIndex:   endIndex,
-					StartIndex: startIndex,
-				},
+				Range: r,
 			},
-		},
-	}
+		}
+	})
 
 	_, err = service.Documents.BatchUpdate(documentID, &docs.BatchUpdateDocumentRequest{
 		Requests: requests,
@@ -117,69 +139,123 @@ func runAlignParagraph(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+func runConvertList(cmd *cobra.Command, args []string) error {
+	to, _ := cmd.Flags().GetString("to")
+
+	var preset string
+	var err error
+	switch strings.ToLower(to) {
+	case "bullet", "bullets", "bulleted":
+		preset = defaultBulletPreset
+	case "number", "numbered", "numbers":
+		preset = defaultNumberedPreset
+	default:
+		preset, err = listPreset(to, "")
+		if err != nil {
+			return err
+		}
+	}
+
+	opts := document.ListOptions{Preset: preset, WholeLists: true}
+	opts.Continue, _ = cmd.Flags().GetBool("continue")
+	opts.Restart, _ = cmd.Flags().GetBool("restart")
+
+	return updateList(cmd, args, opts, "✅ List converted to "+preset)
+}
+
 func runCreateBullets(cmd *cobra.Command, args []string) error {
-	return createList(args, "BULLET")
+	return createList(cmd, args, "BULLET_")
 }
 
 func runCreateNumbered(cmd *cobra.Command, args []string) error {
-	return createList(args, "NUMBER")
+	return createList(cmd, args, "NUMBERED_")
 }
 
-func createList(args []string, listType string) error {
+func createList(cmd *cobra.Command, args []string, kind string) error {
+	value, _ := cmd.Flags().GetString("preset")
+
+	preset, err := listPreset(value, kind)
+	if err != nil {
+		return err
+	}
+
+	opts := document.ListOptions{Preset: preset}
+	opts.Continue, _ = cmd.Flags().GetBool("continue")
+	opts.Level, _ = cmd.Flags().GetInt64("level")
+	opts.Restart, _ = cmd.Flags().GetBool("restart")
+
+	if opts.Level < 0 {
+		return fmt.Errorf("invalid level: %d (must not be negative)", opts.Level)
+	}
+
+	return updateList(cmd, args, opts, "✅ List created ("+preset+")")
+}
+
+// updateList applies list options to the command's ranges
+func updateList(cmd *cobra.Command, args []string, opts document.ListOptions, message string) error {
 	ctx := context.Background()
 	documentID := args[0]
 
-	startIndex, err := strconv.ParseInt(args[1], 10, 64)
+	ranges, err := commandRanges(ctx, cmd, documentID, args[1:])
 	if err != nil {
-		return fmt.Errorf("invalid start index: %w", err)
+		return err
 	}
 
-	endIndex, err := strconv.ParseInt(args[2], 10, 64)
+	doc, err := getDocument(ctx, documentID)
 	if err != nil {
-		return fmt.Errorf("invalid end index: %w", err)
+		return err
 	}
 
-	service, err := auth.GetDocsService(ctx)
+	requests, err := document.ListRequests(doc, ranges, opts)
 	if err != nil {
 		return err
 	}
 
-	requests := []*docs.Request{
-		{
-			CreateParagraphBullets: &docs.CreateParagraphBulletsRequest{
-				BulletPreset: listType + "_DISC_CIRCLE_SQUARE",
-				Range: &docs.Range{
-					EndIndex:   endIndex,
-					StartIndex: startIndex,
-				},
-			},
-		},
+	if err := batchUpdate(documentID, requests); err != nil {
+		return fmt.Errorf("error updating list: %w", err)
 	}
 
-	_, err = service.Documents.BatchUpdate(documentID, &docs.BatchUpdateDocumentRequest{
-		Requests: requests,
-	}).Do()
+	fmt.Fprintf(os.Stderr, "%s\n", green(message))
+	return nil
+}
+
+// listPreset validates a bullet preset, which must start with kind if given,
+// and defaults to the kind's usual preset
+func listPreset(value, kind string) (string, error) {
+	if value == "" {
+		if kind == "NUMBERED_" {
+			return defaultNumberedPreset, nil
+		}
+		return defaultBulletPreset, nil
+	}
 
+	preset, err := parseEnum("bullet preset", value, document.BulletPresets)
 	if err != nil {
-		return fmt.Errorf("error creating list: %w", err)
+		return "", err
+	}
+	if !strings.HasPrefix(preset, kind) {
+		return "", fmt.Errorf("invalid bullet preset: %s (must start with %s)", value, kind)
 	}
 
-	fmt.Fprintf(os.Stderr, "%s\n", green("✅ "+listType+" list created"))
-	return nil
+	return preset, nil
 }
 
-func runFormatText(cmd *cobra.Command, args []string) error {
+func runFormatParagraph(cmd *cobra.Command, args []string) error {
 	ctx := context.Background()
 	documentID := args[0]
 
-	startIndex, err := strconv.ParseInt(args[1], 10, 64)
+	paragraphStyle, fields, err := paragraphStyleFlags(cmd)
 	if err != nil {
-		return fmt.Errorf("invalid start index: %w", err)
+		return err
+	}
+
+	if len(fields) == 0 {
+		return fmt.Errorf("no formatting options specified")
 	}
 
-	endIndex, err := strconv.ParseInt(args[2], 10, 64)
+	ranges, err := commandRanges(ctx, cmd, documentID, args[1:])
 	if err != nil {
-		return fmt.Errorf("invalid end index: %w", err)
+		return err
 	}
 
 	service, err := auth.GetDocsService(ctx)
@@ -187,57 +263,75 @@ func runFormatText(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	textStyle := &docs.TextStyle{}
-	fields := []string{}
+	requests := rangeRequests(ranges, func(r *docs.Range) *docs.Request {
+		return &docs.Request{
+			UpdateParagraphStyle: &docs.UpdateParagraphStyleRequest{
+				Fields:         strings.Join(fields, ","),
+				ParagraphStyle: paragraphStyle,
+				Range:          r,
+			},
+		}
+	})
+
+	_, err = service.Documents.BatchUpdate(documentID, &docs.BatchUpdateDocumentRequest{
+		Requests: requests,
+	}).Do()
 
-	bold, _ := cmd.Flags().GetBool("bold")
-	if bold {
-		textStyle.Bold = true
-		fields = append(fields, "bold")
+	if err != nil {
+		return fmt.Errorf("error formatting paragraph: %w", err)
 	}
 
-	italic, _ := cmd.Flags().GetBool("italic")
-	if italic {
-		textStyle.Italic = true
-		fields = append(fields, "italic")
+	fmt.Fprintf(os.Stderr, "%s\n", green("✅ Paragraph formatted"))
+	return nil
+}
+
+func runFormatText(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	documentID := args[0]
+
+	service, err := auth.GetDocsService(ctx)
+	if err != nil {
+		return err
 	}
 
-	underline, _ := cmd.Flags().GetBool("underline")
-	if underline {
-		textStyle.Underline = true
-		fields = append(fields, "underline")
+	textStyle, fields, err := textStyleFlags(ctx, cmd, documentID)
+	if err != nil {
+		return err
 	}
 
-	textColor, _ := cmd.Flags().GetString("color")
-	if textColor != "" {
-		textStyle.ForegroundColor = conversion.ParseColor(textColor)
-		fields = append(fields, "foregroundColor")
+	presetName, _ := cmd.Flags().GetString("preset")
+	if len(fields) == 0 && presetName == "" {
+		return fmt.Errorf("no formatting options specified")
 	}
 
-	fontSize, _ := cmd.Flags().GetFloat64("size")
-	if fontSize > 0 {
-		textStyle.FontSize = &docs.Dimension{
-			Magnitude: fontSize,
-			Unit:      "PT",
+	var preset *conversion.Preset
+	if presetName != "" {
+		preset, err = getPreset(presetName)
+		if err != nil {
+			return err
 		}
-		fields = append(fields, "fontSize")
 	}
 
-	if len(fields) == 0 {
-		return fmt.Errorf("no formatting options specified")
+	ranges, err := commandRanges(ctx, cmd, documentID, args[1:])
+	if err != nil {
+		return err
 	}
 
-	requests := []*docs.Request{
-		{
-			UpdateTextStyle: &docs.UpdateTextStyleRequest{
-				Fields: strings.Join(fields, ","),
-				Range: &docs.Range{
-					EndIndex:   endIndex,
-					StartIndex: startIndex,
+	// Flags are applied after the preset so they override it
+	var requests []*docs.Request
+	for i := len(ranges) - 1; i >= 0; i-- {
+		if preset != nil {
+			requests = append(requests, preset.Requests(ranges[i])...)
+		}
+		if len(fields) > 0 {
+			requests = append(requests, &docs.Request{
+				UpdateTextStyle: &docs.UpdateTextStyleRequest{
+					Fields:    strings.Join(fields, ","),
+					Range:     ranges[i],
+					TextStyle: textStyle,
 				},
-				TextStyle: textStyle,
-			},
-		},
+			})
+		}
 	}
 
 	_, err = service.Documents.BatchUpdate(documentID, &docs.BatchUpdateDocumentRequest{
@@ -256,14 +350,9 @@ func runRemoveBullets(cmd *cobra.Command, args []string) error {
 	ctx := context.Background()
 	documentID := args[0]
 
-	startIndex, err := strconv.ParseInt(args[1], 10, 64)
+	ranges, err := commandRanges(ctx, cmd, documentID, args[1:])
 	if err != nil {
-		return fmt.Errorf("invalid start index: %w", err)
-	}
-
-	endIndex, err := strconv.ParseInt(args[2], 10, 64)
-	if err != nil {
-		return fmt.Errorf("invalid end index: %w", err)
+		return err
 	}
 
 	service, err := auth.GetDocsService(ctx)
@@ -271,16 +360,13 @@ func runRemoveBullets(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	requests := []*docs.Request{
-		{
+	requests := rangeRequests(ranges, func(r *docs.Range) *docs.Request {
+		return &docs.Request{
 			DeleteParagraphBullets: &docs.DeleteParagraphBulletsRequest{
-				Range: &docs.Range{
-					EndIndex:   endIndex,
-					StartIndex: startIndex,
-				},
+				Range: r,
 			},
-		},
-	}
+		}
+	})
 
 	_, err = service.Documents.BatchUpdate(documentID, &docs.BatchUpdateDocumentRequest{
 		Requests: requests,
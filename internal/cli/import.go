@@ -0,0 +1,117 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"google-docs-manager/internal/auth"
+
+	"github.com/spf13/cobra"
+	"google.golang.org/api/drive/v3"
+	"google.golang.org/api/googleapi"
+)
+
+var importFormats = map[string]string{
+	".docx":     "application/vnd.openxmlformats-officedocument.wordprocessingml.document",
+	".htm":      "text/html",
+	".html":     "text/html",
+	".markdown": "text/markdown",
+	".md":       "text/markdown",
+	".odt":      "application/vnd.oasis.opendocument.text",
+	".rtf":      "application/rtf",
+	".txt":      "text/plain",
+}
+
+var importCmd = &cobra.Command{
+	Args:  cobra.ExactArgs(1),
+	RunE:  runImport,
+	Short: "Import a DOCX, ODT, RTF, HTML, text or markdown file as a Google Doc",
+	Use:   "import <file>",
+}
+
+func initImportCommands() {
+	importCmd.Flags().String("folder", "", "Folder ID to create the document in")
+	importCmd.Flags().String("replace", "", "Replace the content of this existing document instead of creating a new one")
+	importCmd.Flags().String("title", "", "Document title (defaults to the file name)")
+}
+
+func runImport(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	path := args[0]
+
+	ext := strings.ToLower(filepath.Ext(path))
+	sourceMimeType, ok := importFormats[ext]
+	if !ok {
+		return fmt.Errorf("unsupported file type: %s (must be .docx, .odt, .rtf, .html, .txt, or .md)", ext)
+	}
+
+	title, _ := cmd.Flags().GetString("title")
+	folderID, _ := cmd.Flags().GetString("folder")
+	replaceID, _ := cmd.Flags().GetString("replace")
+
+	if replaceID != "" && folderID != "" {
+		return fmt.Errorf("--folder cannot be used with --replace")
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("error reading file: %w", err)
+	}
+	defer f.Close()
+
+	driveService, err := auth.GetDriveService(ctx)
+	if err != nil {
+		return err
+	}
+
+	if replaceID != "" {
+		// Updating the media of an existing Doc converts the upload in place,
+		// keeping the document ID, sharing settings and revision history
+		metadata := &drive.File{}
+		if title != "" {
+			metadata.Name = title
+		}
+
+		file, err := driveService.Files.Update(replaceID, metadata).
+			Media(f, googleapi.ContentType(sourceMimeType)).
+			Fields("id", "name").
+			Do()
+		if err != nil {
+			return fmt.Errorf("error replacing document content: %w", err)
+		}
+
+		fmt.Fprintf(os.Stderr, "%s\n", green("✅ Document content replaced: "+file.Name))
+		fmt.Fprintf(os.Stderr, "%s\n", green("   ID: "+file.Id))
+		fmt.Println(file.Id)
+		return nil
+	}
+
+	if title == "" {
+		title = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+
+	metadata := &drive.File{
+		MimeType: docsMimeType,
+		Name:     title,
+	}
+	if folderID != "" {
+		metadata.Parents = []string{folderID}
+	}
+
+	file, err := driveService.Files.Create(metadata).
+		Media(f, googleapi.ContentType(sourceMimeType)).
+		Fields("id", "name").
+		Do()
+	if err != nil {
+		return fmt.Errorf("error importing file: %w", err)
+	}
+
+	fmt.Fprintf(os.Stderr, "%s\n", green("✅ Document imported: "+file.Name))
+	fmt.Fprintf(os.Stderr, "%s\n", green("   ID: "+file.Id))
+	fmt.Println(file.Id)
+
+	return nil
+}
@@ -0,0 +1,224 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"google-docs-manager/internal/document"
+
+	"github.com/spf13/cobra"
+	"google.golang.org/api/docs/v1"
+)
+
+// tocIndent is the indentation per heading level of table of contents entries, in points
+const tocIndent = 18
+
+var mergeCmd = &cobra.Command{
+	Args:  cobra.MinimumNArgs(2),
+	RunE:  runMerge,
+	Short: "Append the content of source documents to a target document",
+	Use:   "merge <target-document-id> <source-document-id>...",
+}
+
+func initMergeCommands() {
+	mergeCmd.Flags().Int("heading-offset", 1, "Number of levels to demote source headings by (titles become level offset headings)")
+	mergeCmd.Flags().Bool("page-breaks", true, "Insert a page break before each source document")
+	mergeCmd.Flags().Bool("toc", false, "Insert a linked table of contents at the top of the target")
+	mergeCmd.Flags().Int("toc-depth", 3, "Deepest heading level listed in the table of contents")
+}
+
+func runMerge(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	targetID := args[0]
+	sourceIDs := args[1:]
+
+	offset, _ := cmd.Flags().GetInt("heading-offset")
+	pageBreaks, _ := cmd.Flags().GetBool("page-breaks")
+	toc, _ := cmd.Flags().GetBool("toc")
+	tocDepth, _ := cmd.Flags().GetInt("toc-depth")
+
+	if offset < 0 || offset > 5 {
+		return fmt.Errorf("invalid heading offset: %d (must be 0-5)", offset)
+	}
+
+	// Every source is read first and merged in one batch, so a failure leaves
+	// the target unchanged
+	var sources []*docs.Document
+	for _, sourceID := range sourceIDs {
+		source, err := getDocument(ctx, sourceID)
+		if err != nil {
+			return err
+		}
+		sources = append(sources, source)
+	}
+
+	target, err := getDocument(ctx, targetID)
+	if err != nil {
+		return err
+	}
+
+	index, requests := appendIndex(target)
+	hasContent := documentEnd(target) > 2
+
+	for _, source := range sources {
+		// Separate the source from earlier content with a page break, which is
+		// inserted with a newline of its own
+		if pageBreaks && hasContent {
+			requests = append(requests, &docs.Request{
+				InsertPageBreak: &docs.InsertPageBreakRequest{
+					Location: &docs.Location{Index: index},
+				},
+			})
+			index += 2
+		}
+
+		// The copy goes before the empty final paragraph, where the next source is appended
+		elements := document.ElementsInRange(source.Body.Content, 1, documentEnd(source))
+		copyRequests, length := document.CopyElements(source, document.ShiftHeadings(elements, offset), index)
+		requests = append(requests, copyRequests...)
+		index += length
+		hasContent = true
+	}
+
+	if err := batchUpdate(targetID, requests); err != nil {
+		return fmt.Errorf("error merging documents (target left unchanged): %w", err)
+	}
+
+	for _, source := range sources {
+		fmt.Fprintf(os.Stderr, "%s\n", green("✅ Merged: "+source.Title))
+	}
+
+	if toc {
+		// Heading IDs of the merged content are only known once it is inserted
+		target, err := getDocument(ctx, targetID)
+		if err != nil {
+			return err
+		}
+
+		requests := tableOfContentsRequests(document.GetStructure(target), tocDepth, 1)
+		if len(requests) > 0 {
+			requests = append(openFirstParagraph(target), requests...)
+			if err := batchUpdate(targetID, requests); err != nil {
+				return fmt.Errorf("error inserting table of contents: %w", err)
+			}
+			fmt.Fprintf(os.Stderr, "%s\n", green("✅ Table of contents inserted"))
+		}
+	}
+
+	return nil
+}
+
+// appendIndex returns where content appended to a document is inserted, with
+// the requests preparing it. An empty final paragraph is filled rather than
+// followed by another one.
+func appendIndex(doc *docs.Document) (int64, []*docs.Request) {
+	end := documentEnd(doc)
+	last := doc.Body.Content[len(doc.Body.Content)-1]
+
+	if last.Paragraph != nil && last.EndIndex-last.StartIndex == 1 {
+		return last.StartIndex, nil
+	}
+
+	return insertionIndex(end, end)
+}
+
+// openFirstParagraph returns the request opening a paragraph at the start of
+// the body when it does not begin with one, so content can go at index 1
+func openFirstParagraph(doc *docs.Document) []*docs.Request {
+	for _, element := range doc.Body.Content {
+		if element.StartIndex < 1 {
+			continue
+		}
+		if element.StartIndex == 1 && element.Paragraph != nil {
+			return nil
+		}
+		break
+	}
+
+	return []*docs.Request{
+		{
+			InsertText: &docs.InsertTextRequest{
+				Location: &docs.Location{Index: 1},
+				Text:     "\n",
+			},
+		},
+	}
+}
+
+// tableOfContentsRequests insert at index one paragraph per heading, linked to
+// the heading and indented by level, followed by a page break. The API cannot
+// create a real table of contents.
+func tableOfContentsRequests(sections []document.Section, depth int, index int64) []*docs.Request {
+	var entries []document.Section
+	for _, section := range sections {
+		if section.Level <= depth && section.HeadingID != "" && strings.TrimSpace(section.Title) != "" {
+			entries = append(entries, section)
+		}
+	}
+
+	if len(entries) == 0 {
+		return nil
+	}
+
+	// The page break paragraph would take the style of the first paragraph
+	requests := []*docs.Request{
+		{
+			InsertPageBreak: &docs.InsertPageBreakRequest{
+				Location: &docs.Location{Index: index},
+			},
+		},
+		{
+			UpdateParagraphStyle: &docs.UpdateParagraphStyleRequest{
+				Fields:         document.ParagraphStyleFields,
+				ParagraphStyle: &docs.ParagraphStyle{NamedStyleType: "NORMAL_TEXT"},
+				Range: &docs.Range{
+					EndIndex:   index + 2,
+					StartIndex: index,
+				},
+			},
+		},
+	}
+
+	// Entries are inserted in reverse order, each one at index
+	for i := len(entries) - 1; i >= 0; i-- {
+		entry := entries[i]
+		length := document.TextLength(entry.Title)
+
+		requests = append(requests,
+			&docs.Request{
+				InsertText: &docs.InsertTextRequest{
+					Location: &docs.Location{Index: index},
+					Text:     entry.Title + "\n",
+				},
+			},
+			&docs.Request{
+				UpdateParagraphStyle: &docs.UpdateParagraphStyleRequest{
+					Fields: document.ParagraphStyleFields,
+					ParagraphStyle: &docs.ParagraphStyle{
+						IndentFirstLine: &docs.Dimension{Magnitude: float64((entry.Level - 1) * tocIndent), Unit: "PT"},
+						IndentStart:     &docs.Dimension{Magnitude: float64((entry.Level - 1) * tocIndent), Unit: "PT"},
+						NamedStyleType:  "NORMAL_TEXT",
+					},
+					Range: &docs.Range{
+						EndIndex:   index + length + 1,
+						StartIndex: index,
+					},
+				},
+			},
+			&docs.Request{
+				UpdateTextStyle: &docs.UpdateTextStyleRequest{
+					Fields: document.TextStyleFields,
+					Range: &docs.Range{
+						EndIndex:   index + length,
+						StartIndex: index,
+					},
+					TextStyle: &docs.TextStyle{Link: &docs.Link{HeadingId: entry.HeadingID}},
+				},
+			},
+		)
+	}
+
+	return requests
+}
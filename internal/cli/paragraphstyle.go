@@ -0,0 +1,308 @@
+package cli
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"google-docs-manager/internal/conversion"
+	"google-docs-manager/internal/document"
+
+	"github.com/spf13/cobra"
+	"google.golang.org/api/docs/v1"
+)
+
+var (
+	// borderSides maps --border sides to paragraph style fields
+	borderSides = []struct {
+		field string
+		set   func(*docs.ParagraphStyle, *docs.ParagraphBorder)
+		side  string
+	}{
+		{"borderTop", func(s *docs.ParagraphStyle, b *docs.ParagraphBorder) { s.BorderTop = b }, "top"},
+		{"borderBottom", func(s *docs.ParagraphStyle, b *docs.ParagraphBorder) { s.BorderBottom = b }, "bottom"},
+		{"borderLeft", func(s *docs.ParagraphStyle, b *docs.ParagraphBorder) { s.BorderLeft = b }, "left"},
+		{"borderRight", func(s *docs.ParagraphStyle, b *docs.ParagraphBorder) { s.BorderRight = b }, "right"},
+		{"borderBetween", func(s *docs.ParagraphStyle, b *docs.ParagraphBorder) { s.BorderBetween = b }, "between"},
+	}
+
+	// paragraphDimensions are the paragraph style lengths set in points
+	paragraphDimensions = []struct {
+		field string
+		flag  string
+		set   func(*docs.ParagraphStyle, *docs.Dimension)
+		usage string
+	}{
+		{"indentEnd", "indent-end", func(s *docs.ParagraphStyle, d *docs.Dimension) { s.IndentEnd = d }, "Indent from the end (right in left-to-right text), in points"},
+		{"indentFirstLine", "indent-first-line", func(s *docs.ParagraphStyle, d *docs.Dimension) { s.IndentFirstLine = d }, "Indent of the first line, in points"},
+		{"indentStart", "indent-start", func(s *docs.ParagraphStyle, d *docs.Dimension) { s.IndentStart = d }, "Indent from the start (left in left-to-right text), in points"},
+		{"spaceAbove", "space-above", func(s *docs.ParagraphStyle, d *docs.Dimension) { s.SpaceAbove = d }, "Space above the paragraph, in points"},
+		{"spaceBelow", "space-below", func(s *docs.ParagraphStyle, d *docs.Dimension) { s.SpaceBelow = d }, "Space below the paragraph, in points"},
+	}
+
+	// paragraphStyleToggles are the on/off paragraph style properties, each
+	// with a --no- flag that explicitly turns it off
+	paragraphStyleToggles = []struct {
+		field   string
+		flag    string
+		goField string
+		set     func(*docs.ParagraphStyle, bool)
+		usage   string
+	}{
+		{"keepLinesTogether", "keep-lines-together", "KeepLinesTogether", func(s *docs.ParagraphStyle, v bool) { s.KeepLinesTogether = v }, "keep all lines of the paragraph on the same page"},
+		{"keepWithNext", "keep-with-next", "KeepWithNext", func(s *docs.ParagraphStyle, v bool) { s.KeepWithNext = v }, "keep the paragraph on the same page as the next one"},
+		{"pageBreakBefore", "page-break-before", "PageBreakBefore", func(s *docs.ParagraphStyle, v bool) { s.PageBreakBefore = v }, "start the paragraph on a new page"},
+	}
+
+	validAlignments     = []string{"START", "CENTER", "END", "JUSTIFIED"}
+	validDashStyles     = []string{"SOLID", "DOT", "DASH"}
+	validDirections     = []string{"LEFT_TO_RIGHT", "RIGHT_TO_LEFT"}
+	validNamedStyleType = []string{"NORMAL_TEXT", "TITLE", "SUBTITLE", "HEADING_1", "HEADING_2", "HEADING_3", "HEADING_4", "HEADING_5", "HEADING_6"}
+)
+
+// addParagraphStyleFlags adds the paragraph style flags to a command
+func addParagraphStyleFlags(cmd *cobra.Command) {
+	for _, dimension := range paragraphDimensions {
+		cmd.Flags().Float64(dimension.flag, 0, dimension.usage)
+	}
+	for _, toggle := range paragraphStyleToggles {
+		cmd.Flags().Bool(toggle.flag, false, "Do "+toggle.usage)
+		cmd.Flags().Bool("no-"+toggle.flag, false, "Do not "+toggle.usage)
+	}
+
+	cmd.Flags().String("align", "", "Alignment: "+strings.Join(validAlignments, ", "))
+	cmd.Flags().StringSlice("border", nil, "Add borders on these sides: top, bottom, left, right, between, or all")
+	cmd.Flags().String("border-color", "#000000", "Border color (hex)")
+	cmd.Flags().String("border-dash", "SOLID", "Border dash style: "+strings.Join(validDashStyles, ", "))
+	cmd.Flags().Float64("border-padding", 1, "Padding between the border and the text, in points")
+	cmd.Flags().Float64("border-width", 1, "Border width in points")
+	cmd.Flags().Bool("clear", false, "Reset the paragraphs to their named style before applying other flags")
+	cmd.Flags().String("direction", "", "Text direction: LEFT_TO_RIGHT (ltr) or RIGHT_TO_LEFT (rtl)")
+	cmd.Flags().Float64("line-spacing", 0, "Line spacing as a percentage (100 is single, 200 double)")
+	cmd.Flags().StringSlice("no-border", nil, "Remove borders on these sides: top, bottom, left, right, between, or all")
+	cmd.Flags().Bool("no-shading", false, "Remove the background shading")
+	cmd.Flags().String("shading", "", "Background shading color (hex, e.g., #F3F3F3)")
+	cmd.Flags().String("style", "", "Named style: NORMAL_TEXT, TITLE, SUBTITLE or HEADING_1 to HEADING_6 (or a heading level 1-6)")
+}
+
+// paragraphStyleFlags builds a paragraph style and its field mask from the
+// paragraph style flags
+func paragraphStyleFlags(cmd *cobra.Command) (*docs.ParagraphStyle, []string, error) {
+	paragraphStyle := &docs.ParagraphStyle{}
+	fields := []string{}
+	flags := cmd.Flags()
+
+	reset, _ := flags.GetBool("clear")
+	if reset {
+		// Every field in the mask without a value reverts to the named style,
+		// which itself is only changed by --style
+		for _, field := range strings.Split(document.ParagraphStyleFields, ",") {
+			if field != "namedStyleType" {
+				fields = append(fields, field)
+			}
+		}
+	}
+
+	set := func(field string) {
+		if !slices.Contains(fields, field) {
+			fields = append(fields, field)
+		}
+	}
+
+	style, _ := flags.GetString("style")
+	if style != "" {
+		if level, err := strconv.Atoi(style); err == nil && level >= 1 && level <= 6 {
+			style = fmt.Sprintf("HEADING_%d", level)
+		}
+
+		namedStyleType, err := parseEnum("named style", style, validNamedStyleType)
+		if err != nil {
+			return nil, nil, err
+		}
+		paragraphStyle.NamedStyleType = namedStyleType
+		set("namedStyleType")
+	}
+
+	align, _ := flags.GetString("align")
+	if align != "" {
+		alignment, err := parseEnum("alignment", align, validAlignments)
+		if err != nil {
+			return nil, nil, err
+		}
+		paragraphStyle.Alignment = alignment
+		set("alignment")
+	}
+
+	direction, _ := flags.GetString("direction")
+	if direction != "" {
+		switch strings.ToLower(direction) {
+		case "ltr":
+			direction = "LEFT_TO_RIGHT"
+		case "rtl":
+			direction = "RIGHT_TO_LEFT"
+		}
+
+		parsed, err := parseEnum("direction", direction, validDirections)
+		if err != nil {
+			return nil, nil, err
+		}
+		paragraphStyle.Direction = parsed
+		set("direction")
+	}
+
+	if flags.Changed("line-spacing") {
+		lineSpacing, _ := flags.GetFloat64("line-spacing")
+		if lineSpacing <= 0 {
+			return nil, nil, fmt.Errorf("invalid line spacing: %g (must be a positive percentage)", lineSpacing)
+		}
+		paragraphStyle.LineSpacing = lineSpacing
+		set("lineSpacing")
+	}
+
+	for _, dimension := range paragraphDimensions {
+		if !flags.Changed(dimension.flag) {
+			continue
+		}
+
+		value, _ := flags.GetFloat64(dimension.flag)
+		if value < 0 {
+			return nil, nil, fmt.Errorf("invalid --%s: %g (must not be negative)", dimension.flag, value)
+		}
+		dimension.set(paragraphStyle, points(value))
+		set(dimension.field)
+	}
+
+	for _, toggle := range paragraphStyleToggles {
+		on, _ := flags.GetBool(toggle.flag)
+		off, _ := flags.GetBool("no-" + toggle.flag)
+
+		if on && off {
+			return nil, nil, fmt.Errorf("--%s and --no-%s cannot be combined", toggle.flag, toggle.flag)
+		}
+		if on {
+			toggle.set(paragraphStyle, true)
+			set(toggle.field)
+		}
+		if off {
+			// Explicitly false rather than inherited from the named style
+			toggle.set(paragraphStyle, false)
+			paragraphStyle.ForceSendFields = append(paragraphStyle.ForceSendFields, toggle.goField)
+			set(toggle.field)
+		}
+	}
+
+	shading, _ := flags.GetString("shading")
+	noShading, _ := flags.GetBool("no-shading")
+	if shading != "" && noShading {
+		return nil, nil, fmt.Errorf("--shading and --no-shading cannot be combined")
+	}
+	if shading != "" {
+		color := conversion.ParseColor(shading)
+		if color == nil {
+			return nil, nil, fmt.Errorf("invalid color: %s (must be a hex color like #FF0000)", shading)
+		}
+		paragraphStyle.Shading = &docs.Shading{BackgroundColor: color}
+		set("shading")
+	}
+	if noShading {
+		set("shading")
+	}
+
+	border, _ := flags.GetStringSlice("border")
+	noBorder, _ := flags.GetStringSlice("no-border")
+
+	addSides, err := parseBorderSides(border)
+	if err != nil {
+		return nil, nil, err
+	}
+	removeSides, err := parseBorderSides(noBorder)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(addSides) > 0 {
+		// A border can only be set in its entirety
+		borderColor, _ := flags.GetString("border-color")
+		borderDash, _ := flags.GetString("border-dash")
+		borderPadding, _ := flags.GetFloat64("border-padding")
+		borderWidth, _ := flags.GetFloat64("border-width")
+
+		color := conversion.ParseColor(borderColor)
+		if color == nil {
+			return nil, nil, fmt.Errorf("invalid color: %s (must be a hex color like #FF0000)", borderColor)
+		}
+		dashStyle, err := parseEnum("border dash style", borderDash, validDashStyles)
+		if err != nil {
+			return nil, nil, err
+		}
+		if borderPadding < 0 || borderWidth < 0 {
+			return nil, nil, fmt.Errorf("border padding and width must not be negative")
+		}
+
+		for _, side := range borderSides {
+			if slices.Contains(addSides, side.side) {
+				side.set(paragraphStyle, &docs.ParagraphBorder{
+					Color:     color,
+					DashStyle: dashStyle,
+					Padding:   points(borderPadding),
+					Width:     points(borderWidth),
+				})
+				set(side.field)
+			}
+		}
+	}
+
+	for _, side := range borderSides {
+		if slices.Contains(removeSides, side.side) {
+			if slices.Contains(addSides, side.side) {
+				return nil, nil, fmt.Errorf("--border and --no-border cannot both include %s", side.side)
+			}
+			set(side.field)
+		}
+	}
+
+	return paragraphStyle, fields, nil
+}
+
+// parseBorderSides validates border sides, expanding all to every side
+func parseBorderSides(values []string) ([]string, error) {
+	var sides []string
+	for _, value := range values {
+		value = strings.ToLower(strings.TrimSpace(value))
+		if value == "all" {
+			for _, side := range borderSides {
+				sides = append(sides, side.side)
+			}
+			continue
+		}
+
+		valid := false
+		for _, side := range borderSides {
+			valid = valid || side.side == value
+		}
+		if !valid {
+			return nil, fmt.Errorf("invalid border side: %s (must be top, bottom, left, right, between, or all)", value)
+		}
+		sides = append(sides, value)
+	}
+	return sides, nil
+}
+
+// parseEnum validates an enum value case-insensitively, accepting dashes for underscores
+func parseEnum(name, value string, valid []string) (string, error) {
+	normalized := strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(value), "-", "_"))
+	if !slices.Contains(valid, normalized) {
+		return "", fmt.Errorf("invalid %s: %s (must be %s)", name, value, strings.Join(valid, ", "))
+	}
+	return normalized, nil
+}
+
+// points returns a dimension in points, sending zero explicitly
+func points(value float64) *docs.Dimension {
+	return &docs.Dimension{
+		ForceSendFields: []string{"Magnitude"},
+		Magnitude:       value,
+		Unit:            "PT",
+	}
+}
@@ -0,0 +1,56 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"google-docs-manager/internal/conversion"
+)
+
+const presetsFile = "styles.yaml"
+
+// presetsPath returns the path of the style presets file
+func presetsPath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".config", "google-docs-manager", presetsFile)
+}
+
+// loadPresets reads the style presets file. Without one there are no presets.
+func loadPresets() (map[string]*conversion.Preset, error) {
+	data, err := os.ReadFile(presetsPath())
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading presets: %w", err)
+	}
+
+	return conversion.ParsePresets(data)
+}
+
+// getPreset returns a style preset by name
+func getPreset(name string) (*conversion.Preset, error) {
+	presets, err := loadPresets()
+	if err != nil {
+		return nil, err
+	}
+
+	preset, ok := presets[name]
+	if !ok {
+		if len(presets) == 0 {
+			return nil, fmt.Errorf("preset '%s' not found: no presets defined in %s", name, presetsPath())
+		}
+		return nil, fmt.Errorf("preset '%s' not found (available: %s)", name, strings.Join(slices.Sorted(maps.Keys(presets)), ", "))
+	}
+
+	return preset, nil
+}
@@ -0,0 +1,89 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"google-docs-manager/internal/document"
+
+	"github.com/spf13/cobra"
+	"google.golang.org/api/docs/v1"
+)
+
+// addRangeFlags adds the range selector flags to a command taking start and
+// end indices
+func addRangeFlags(cmd *cobra.Command) {
+	cmd.Flags().String("match", "", "Select occurrences of this exact text instead of indices")
+	cmd.Flags().String("occurrence", "1", "Which --match or --regex occurrence to select (a number or all)")
+	cmd.Flags().Int("paragraph", 0, "Select the Nth paragraph (1-based, within --section if given)")
+	cmd.Flags().String("regex", "", "Select occurrences of this regular expression instead of indices")
+	cmd.Flags().String("section", "", "Select a section, or limit the other selectors to it")
+}
+
+// rangeArgs accepts a document ID, optional start and end indices, and extra
+// trailing arguments
+func rangeArgs(extra int) cobra.PositionalArgs {
+	return func(cmd *cobra.Command, args []string) error {
+		if len(args) != 1+extra && len(args) != 3+extra {
+			return fmt.Errorf("accepts %d or %d arg(s), received %d", 1+extra, 3+extra, len(args))
+		}
+		return nil
+	}
+}
+
+// rangeSelector reads the range selector flags of a command
+func rangeSelector(cmd *cobra.Command) document.RangeSelector {
+	selector := document.RangeSelector{}
+	selector.Match, _ = cmd.Flags().GetString("match")
+	selector.Occurrence, _ = cmd.Flags().GetString("occurrence")
+	selector.Paragraph, _ = cmd.Flags().GetInt("paragraph")
+	selector.Regex, _ = cmd.Flags().GetString("regex")
+	selector.Section, _ = cmd.Flags().GetString("section")
+	return selector
+}
+
+// commandRanges returns the ranges a command applies to, either from start and
+// end index arguments or by resolving the range selector flags against the document
+func commandRanges(ctx context.Context, cmd *cobra.Command, documentID string, indexArgs []string) ([]*docs.Range, error) {
+	selector := rangeSelector(cmd)
+
+	if len(indexArgs) == 2 {
+		if !selector.IsEmpty() {
+			return nil, fmt.Errorf("use either start and end indices or range selector flags, not both")
+		}
+
+		startIndex, err := strconv.ParseInt(indexArgs[0], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid start index: %w", err)
+		}
+
+		endIndex, err := strconv.ParseInt(indexArgs[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid end index: %w", err)
+		}
+
+		return []*docs.Range{{EndIndex: endIndex, StartIndex: startIndex}}, nil
+	}
+
+	if selector.IsEmpty() {
+		return nil, fmt.Errorf("a range is required: <start-index> <end-index>, or --match, --regex, --section or --paragraph")
+	}
+
+	doc, err := getDocument(ctx, documentID)
+	if err != nil {
+		return nil, err
+	}
+
+	return document.ResolveRanges(doc, selector)
+}
+
+// rangeRequests builds one request per range, from the last range to the
+// first so that requests changing the text length keep earlier ranges valid
+func rangeRequests(ranges []*docs.Range, build func(*docs.Range) *docs.Request) []*docs.Request {
+	requests := make([]*docs.Request, 0, len(ranges))
+	for i := len(ranges) - 1; i >= 0; i-- {
+		requests = append(requests, build(ranges[i]))
+	}
+	return requests
+}
@@ -0,0 +1,241 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"google-docs-manager/internal/auth"
+	"google-docs-manager/internal/document"
+
+	"github.com/spf13/cobra"
+	"google.golang.org/api/docs/v1"
+)
+
+// previewContext is the number of characters of context shown around previewed matches
+const previewContext = 30
+
+// replacePreview is a match listed by replace --preview
+type replacePreview struct {
+	After       string `json:"after"`
+	Before      string `json:"before"`
+	EndIndex    int64  `json:"endIndex"`
+	Replacement string `json:"replacement"`
+	SectionPath string `json:"sectionPath,omitempty"`
+	StartIndex  int64  `json:"startIndex"`
+	Text        string `json:"text"`
+}
+
+var replaceCmd = &cobra.Command{
+	Args:  cobra.ExactArgs(3),
+	RunE:  runReplace,
+	Short: "Replace text, literally or by regular expression",
+	Use:   "replace <document-id> <pattern> <replacement>",
+}
+
+func initReplaceCommands() {
+	addTextStyleFlags(replaceCmd)
+	replaceCmd.Flags().Bool("ignore-case", false, "Match case-insensitively")
+	replaceCmd.Flags().Bool("preview", false, "Output the matches and their replacements as JSON without changing the document")
+	replaceCmd.Flags().Bool("regex", false, "Treat the pattern as a regular expression; the replacement may use $1 or ${name}")
+	replaceCmd.Flags().String("section", "", "Only replace within this section")
+	replaceCmd.Flags().String("tab", "", "Only replace within this tab (ID or title); without it, plain literal replacements cover every tab while --regex, --section, --preview and style flags use the first tab")
+}
+
+func runReplace(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	documentID := args[0]
+	pattern := args[1]
+	replacement := args[2]
+
+	ignoreCase, _ := cmd.Flags().GetBool("ignore-case")
+	preview, _ := cmd.Flags().GetBool("preview")
+	regexMode, _ := cmd.Flags().GetBool("regex")
+	sectionName, _ := cmd.Flags().GetString("section")
+	tab, _ := cmd.Flags().GetString("tab")
+
+	textStyle, fields, err := textStyleFlags(ctx, cmd, documentID)
+	if err != nil {
+		return err
+	}
+
+	if pattern == "" {
+		return fmt.Errorf("pattern must not be empty")
+	}
+
+	service, err := auth.GetDocsService(ctx)
+	if err != nil {
+		return err
+	}
+
+	call := service.Documents.Get(documentID)
+	if tab != "" {
+		call = call.IncludeTabsContent(true)
+	}
+
+	doc, err := call.Do()
+	if err != nil {
+		return fmt.Errorf("error getting document: %w", err)
+	}
+
+	tabID := ""
+	if tab != "" {
+		doc, tabID, err = document.TabDocument(doc, tab)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Literal replacements without a section or style are left to the API
+	if !regexMode && sectionName == "" && len(fields) == 0 && !preview {
+		request := &docs.ReplaceAllTextRequest{
+			ContainsText: &docs.SubstringMatchCriteria{
+				MatchCase: !ignoreCase,
+				Text:      pattern,
+			},
+			ReplaceText: replacement,
+		}
+		if tabID != "" {
+			request.TabsCriteria = &docs.TabsCriteria{TabIds: []string{tabID}}
+		}
+
+		result, err := service.Documents.BatchUpdate(documentID, &docs.BatchUpdateDocumentRequest{
+			Requests: []*docs.Request{{ReplaceAllText: request}},
+		}).Do()
+		if err != nil {
+			return fmt.Errorf("error replacing text: %w", err)
+		}
+
+		changed := int64(0)
+		if len(result.Replies) > 0 && result.Replies[0].ReplaceAllText != nil {
+			changed = result.Replies[0].ReplaceAllText.OccurrencesChanged
+		}
+		fmt.Fprintf(os.Stderr, "%s\n", green(fmt.Sprintf("✅ Replaced %d occurrences", changed)))
+		return nil
+	}
+
+	expr := pattern
+	if !regexMode {
+		expr = regexp.QuoteMeta(pattern)
+	}
+	if ignoreCase {
+		expr = "(?i)" + expr
+	}
+
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return fmt.Errorf("invalid regex: %w", err)
+	}
+
+	paragraphs := document.Paragraphs(doc.Body.Content)
+	if sectionName != "" {
+		section, err := document.FindSection(doc, sectionName)
+		if err != nil {
+			return err
+		}
+
+		var scoped []*document.TextParagraph
+		for _, paragraph := range paragraphs {
+			if paragraph.StartIndex >= section.StartIndex && paragraph.StartIndex < section.BodyEndIndex {
+				scoped = append(scoped, paragraph)
+			}
+		}
+		paragraphs = scoped
+	}
+
+	matches := document.FindMatches(paragraphs, re)
+	replacements := make([]string, len(matches))
+	for i, match := range matches {
+		replacements[i] = replacement
+		if regexMode {
+			replacements[i] = string(re.ExpandString(nil, replacement, match.Paragraph.Text, match.Groups))
+		}
+	}
+
+	if preview {
+		return printReplacePreview(doc, matches, replacements)
+	}
+
+	if len(matches) == 0 {
+		fmt.Fprintf(os.Stderr, "%s\n", green("✅ Replaced 0 occurrences"))
+		return nil
+	}
+
+	// Each replacement is inserted after its match, so it takes the match's
+	// style, before the match is deleted. Matches are replaced from the last
+	// one so earlier indices stay valid.
+	var requests []*docs.Request
+	for i := len(matches) - 1; i >= 0; i-- {
+		r := matches[i].Range()
+		r.TabId = tabID
+		length := document.TextLength(replacements[i])
+
+		if length > 0 {
+			requests = append(requests, &docs.Request{
+				InsertText: &docs.InsertTextRequest{
+					Location: &docs.Location{Index: r.EndIndex, TabId: tabID},
+					Text:     replacements[i],
+				},
+			})
+		}
+
+		requests = append(requests, &docs.Request{
+			DeleteContentRange: &docs.DeleteContentRangeRequest{Range: r},
+		})
+
+		if length > 0 && len(fields) > 0 {
+			requests = append(requests, &docs.Request{
+				UpdateTextStyle: &docs.UpdateTextStyleRequest{
+					Fields: strings.Join(fields, ","),
+					Range: &docs.Range{
+						EndIndex:   r.StartIndex + length,
+						StartIndex: r.StartIndex,
+						TabId:      tabID,
+					},
+					TextStyle: textStyle,
+				},
+			})
+		}
+	}
+
+	_, err = service.Documents.BatchUpdate(documentID, &docs.BatchUpdateDocumentRequest{
+		Requests: requests,
+	}).Do()
+
+	if err != nil {
+		return fmt.Errorf("error replacing text: %w", err)
+	}
+
+	fmt.Fprintf(os.Stderr, "%s\n", green(fmt.Sprintf("✅ Replaced %d occurrences", len(matches))))
+	return nil
+}
+
+// printReplacePreview outputs the matches as JSON, with their section, context and replacement
+func printReplacePreview(doc *docs.Document, matches []*document.TextMatch, replacements []string) error {
+	sections := document.GetStructure(doc)
+	previews := []replacePreview{}
+
+	for i, match := range matches {
+		r := match.Range()
+		start, end := match.Groups[0], match.Groups[1]
+		before, after := match.Paragraph.Context(start, end, previewContext)
+
+		preview := replacePreview{
+			After:       after,
+			Before:      before,
+			EndIndex:    r.EndIndex,
+			Replacement: replacements[i],
+			StartIndex:  r.StartIndex,
+			Text:        match.Paragraph.Text[start:end],
+		}
+		if section := document.SectionAt(sections, r.StartIndex); section != nil {
+			preview.SectionPath = section.Path()
+		}
+		previews = append(previews, preview)
+	}
+
+	fmt.Fprintf(os.Stderr, "%d matches\n", len(matches))
+	return printJSON(previews)
+}
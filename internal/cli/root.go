@@ -24,25 +24,55 @@ func Execute() error {
 }
 
 func initCommands() {
+	initChecklistCommands()
+	initContentCommands()
+	initCopyStyleCommands()
 	initDocumentCommands()
+	initExportCommands()
+	initFindCommands()
 	initFormattingCommands()
 	initImageCommands()
+	initImportCommands()
+	initMergeCommands()
+	initReplaceCommands()
+	initSectionCommands()
+	initSplitCommands()
+	initStylesCommands()
 	initTableCommands()
 
 	// Document operations
 	rootCmd.AddCommand(alignParagraphCmd)
+	rootCmd.AddCommand(applyASTCmd)
+	rootCmd.AddCommand(astSchemaCmd)
+	rootCmd.AddCommand(checklistCmd)
+	rootCmd.AddCommand(convertListCmd)
 	rootCmd.AddCommand(copyCmd)
+	rootCmd.AddCommand(copyStyleCmd)
 	rootCmd.AddCommand(createBulletsCmd)
 	rootCmd.AddCommand(createCmd)
 	rootCmd.AddCommand(createNumberedCmd)
+	rootCmd.AddCommand(deleteSectionCmd)
 	rootCmd.AddCommand(deleteTextCmd)
+	rootCmd.AddCommand(duplicateSectionCmd)
+	rootCmd.AddCommand(exportCmd)
+	rootCmd.AddCommand(extractSectionCmd)
+	rootCmd.AddCommand(findCmd)
+	rootCmd.AddCommand(formatParagraphCmd)
 	rootCmd.AddCommand(formatTextCmd)
 	rootCmd.AddCommand(getStructureCmd)
+	rootCmd.AddCommand(importCmd)
 	rootCmd.AddCommand(infoCmd)
 	rootCmd.AddCommand(insertAfterCmd)
+	rootCmd.AddCommand(mergeCmd)
+	rootCmd.AddCommand(moveSectionCmd)
 	rootCmd.AddCommand(readCmd)
 	rootCmd.AddCommand(removeBulletsCmd)
+	rootCmd.AddCommand(renameSectionCmd)
+	rootCmd.AddCommand(replaceCmd)
+	rootCmd.AddCommand(setHTMLCmd)
 	rootCmd.AddCommand(setMarkdownCmd)
+	rootCmd.AddCommand(splitCmd)
+	rootCmd.AddCommand(stylesCmd)
 	rootCmd.AddCommand(updateSectionCmd)
 
 	// Structure operations
@@ -0,0 +1,309 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"google-docs-manager/internal/auth"
+	"google-docs-manager/internal/document"
+
+	"github.com/spf13/cobra"
+	"google.golang.org/api/docs/v1"
+)
+
+var (
+	deleteSectionCmd = &cobra.Command{
+		Args:  cobra.ExactArgs(2),
+		RunE:  runDeleteSection,
+		Short: "Delete a section with its heading and subsections",
+		Use:   "delete-section <document-id> <section>",
+	}
+
+	duplicateSectionCmd = &cobra.Command{
+		Args:  cobra.ExactArgs(2),
+		RunE:  runDuplicateSection,
+		Short: "Insert a copy of a section right after it",
+		Use:   "duplicate-section <document-id> <section>",
+	}
+
+	moveSectionCmd = &cobra.Command{
+		Args:  cobra.ExactArgs(2),
+		RunE:  runMoveSection,
+		Short: "Move a section before or after another section",
+		Use:   "move-section <document-id> <section> --before|--after <other-section>",
+	}
+
+	renameSectionCmd = &cobra.Command{
+		Args:  cobra.ExactArgs(3),
+		RunE:  runRenameSection,
+		Short: "Change the heading text of a section",
+		Use:   "rename-section <document-id> <section> <new-title>",
+	}
+)
+
+func initSectionCommands() {
+	duplicateSectionCmd.Flags().String("title", "", "Heading text of the copy")
+	moveSectionCmd.Flags().String("after", "", "Section to move after (after its subsections)")
+	moveSectionCmd.Flags().String("before", "", "Section to move before")
+}
+
+func runDeleteSection(cmd *cobra.Command, args []string) error {
+	documentID := args[0]
+	sectionName := args[1]
+
+	doc, section, err := getSection(documentID, sectionName)
+	if err != nil {
+		return err
+	}
+
+	requests := []*docs.Request{
+		deleteRangeRequest(section.StartIndex, section.BodyEndIndex, documentEnd(doc)),
+	}
+
+	if err := batchUpdate(documentID, requests); err != nil {
+		return fmt.Errorf("error deleting section: %w", err)
+	}
+
+	fmt.Fprintf(os.Stderr, "%s\n", green("✅ Section '"+section.Title+"' deleted"))
+	return nil
+}
+
+func runDuplicateSection(cmd *cobra.Command, args []string) error {
+	documentID := args[0]
+	sectionName := args[1]
+	title, _ := cmd.Flags().GetString("title")
+
+	doc, section, err := getSection(documentID, sectionName)
+	if err != nil {
+		return err
+	}
+
+	elements := document.ElementsInRange(doc.Body.Content, section.StartIndex, section.BodyEndIndex)
+	index, requests := insertionIndex(section.BodyEndIndex, documentEnd(doc))
+	copyRequests, _ := document.CopyElements(doc, elements, index)
+	requests = append(requests, copyRequests...)
+
+	if title != "" && len(elements) > 0 {
+		// The copied heading starts at the insertion index. Its length is that of
+		// the copy, which differs from the original when elements are skipped.
+		_, headingLength := document.CopyElements(doc, elements[:1], index)
+		requests = append(requests, renameRequests(index, index+headingLength, title)...)
+	}
+
+	if err := batchUpdate(documentID, requests); err != nil {
+		return fmt.Errorf("error duplicating section: %w", err)
+	}
+
+	fmt.Fprintf(os.Stderr, "%s\n", green("✅ Section '"+section.Title+"' duplicated"))
+	return nil
+}
+
+func runMoveSection(cmd *cobra.Command, args []string) error {
+	documentID := args[0]
+	sectionName := args[1]
+	before, _ := cmd.Flags().GetString("before")
+	after, _ := cmd.Flags().GetString("after")
+
+	if (before == "") == (after == "") {
+		return fmt.Errorf("exactly one of --before or --after is required")
+	}
+
+	doc, section, err := getSection(documentID, sectionName)
+	if err != nil {
+		return err
+	}
+
+	otherName := before
+	if after != "" {
+		otherName = after
+	}
+	other, err := document.FindSection(doc, otherName)
+	if err != nil {
+		return err
+	}
+
+	target := other.StartIndex
+	if after != "" {
+		target = other.BodyEndIndex
+	}
+
+	if target > section.StartIndex && target < section.BodyEndIndex {
+		return fmt.Errorf("cannot move section '%s' into itself", section.Title)
+	}
+	if target == section.StartIndex || target == section.BodyEndIndex {
+		fmt.Fprintf(os.Stderr, "%s\n", green("✅ Section '"+section.Title+"' is already in place"))
+		return nil
+	}
+
+	// Copy the section to its new place first, then delete the original, which
+	// moves by the inserted length when it follows the copy
+	end := documentEnd(doc)
+	elements := document.ElementsInRange(doc.Body.Content, section.StartIndex, section.BodyEndIndex)
+	index, requests := insertionIndex(target, end)
+	copyRequests, length := document.CopyElements(doc, elements, index)
+	requests = append(requests, copyRequests...)
+
+	if len(requests) > len(copyRequests) {
+		// The paragraph opened at the end of the document adds a newline
+		end++
+	}
+
+	shift := int64(0)
+	if section.StartIndex >= target {
+		shift = length
+	}
+	end += length
+	requests = append(requests, deleteRangeRequest(section.StartIndex+shift, section.BodyEndIndex+shift, end))
+
+	if err := batchUpdate(documentID, requests); err != nil {
+		return fmt.Errorf("error moving section: %w", err)
+	}
+
+	fmt.Fprintf(os.Stderr, "%s\n", green("✅ Section '"+section.Title+"' moved"))
+	return nil
+}
+
+func runRenameSection(cmd *cobra.Command, args []string) error {
+	documentID := args[0]
+	sectionName := args[1]
+	title := args[2]
+
+	_, section, err := getSection(documentID, sectionName)
+	if err != nil {
+		return err
+	}
+
+	if err := batchUpdate(documentID, renameRequests(section.StartIndex, section.EndIndex, title)); err != nil {
+		return fmt.Errorf("error renaming section: %w", err)
+	}
+
+	fmt.Fprintf(os.Stderr, "%s\n", green("✅ Section '"+section.Title+"' renamed to '"+title+"'"))
+	return nil
+}
+
+// getDocument fetches a document
+func getDocument(ctx context.Context, documentID string) (*docs.Document, error) {
+	service, err := auth.GetDocsService(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	doc, err := service.Documents.Get(documentID).Do()
+	if err != nil {
+		return nil, fmt.Errorf("error getting document: %w", err)
+	}
+
+	return doc, nil
+}
+
+// getSection fetches a document and finds a section in it
+func getSection(documentID, sectionName string) (*docs.Document, *document.Section, error) {
+	doc, err := getDocument(context.Background(), documentID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	section, err := document.FindSection(doc, sectionName)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return doc, section, nil
+}
+
+// batchUpdate applies requests to a document
+func batchUpdate(documentID string, requests []*docs.Request) error {
+	ctx := context.Background()
+
+	service, err := auth.GetDocsService(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, err = service.Documents.BatchUpdate(documentID, &docs.BatchUpdateDocumentRequest{
+		Requests: requests,
+	}).Do()
+	return err
+}
+
+// documentEnd returns the end index of the document body
+func documentEnd(doc *docs.Document) int64 {
+	return doc.Body.Content[len(doc.Body.Content)-1].EndIndex
+}
+
+// insertionIndex returns where content meant for index can be inserted, with
+// the requests preparing it. Content cannot be inserted after the final
+// newline, so at the end of the document an empty normal paragraph is opened
+// after the last one and content goes before it.
+func insertionIndex(index, documentEnd int64) (int64, []*docs.Request) {
+	if index < documentEnd {
+		return index, nil
+	}
+
+	return documentEnd, []*docs.Request{
+		{
+			InsertText: &docs.InsertTextRequest{
+				Location: &docs.Location{Index: documentEnd - 1},
+				Text:     "\n",
+			},
+		},
+		{
+			UpdateParagraphStyle: &docs.UpdateParagraphStyleRequest{
+				Fields: "namedStyleType",
+				ParagraphStyle: &docs.ParagraphStyle{
+					NamedStyleType: "NORMAL_TEXT",
+				},
+				Range: &docs.Range{
+					EndIndex:   documentEnd + 1,
+					StartIndex: documentEnd,
+				},
+			},
+		},
+	}
+}
+
+// deleteRangeRequest deletes [startIndex, endIndex), keeping the final newline
+// of the document, which cannot be deleted
+func deleteRangeRequest(startIndex, endIndex, documentEnd int64) *docs.Request {
+	if endIndex >= documentEnd {
+		endIndex = documentEnd - 1
+	}
+
+	return &docs.Request{
+		DeleteContentRange: &docs.DeleteContentRangeRequest{
+			Range: &docs.Range{
+				EndIndex:   endIndex,
+				StartIndex: startIndex,
+			},
+		},
+	}
+}
+
+// renameRequests replace the text of the heading paragraph at
+// [startIndex, endIndex). The new text is inserted before the old one so it
+// keeps the heading's text style.
+func renameRequests(startIndex, endIndex int64, title string) []*docs.Request {
+	length := document.TextLength(title)
+	requests := []*docs.Request{
+		{
+			InsertText: &docs.InsertTextRequest{
+				Location: &docs.Location{Index: startIndex},
+				Text:     title,
+			},
+		},
+	}
+
+	if endIndex-1 > startIndex {
+		requests = append(requests, &docs.Request{
+			DeleteContentRange: &docs.DeleteContentRangeRequest{
+				Range: &docs.Range{
+					EndIndex:   endIndex - 1 + length,
+					StartIndex: startIndex + length,
+				},
+			},
+		})
+	}
+
+	return requests
+}
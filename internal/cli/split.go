@@ -0,0 +1,212 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"google-docs-manager/internal/document"
+
+	"github.com/spf13/cobra"
+	"google.golang.org/api/docs/v1"
+)
+
+var (
+	extractSectionCmd = &cobra.Command{
+		Args:  cobra.ExactArgs(2),
+		RunE:  runExtractSection,
+		Short: "Copy a section into a new document",
+		Use:   "extract-section <document-id> <section>",
+	}
+
+	splitCmd = &cobra.Command{
+		Args:  cobra.ExactArgs(1),
+		RunE:  runSplit,
+		Short: "Create a document for each section of a heading level",
+		Use:   "split <document-id>",
+	}
+)
+
+// splitResult describes a document created from a section
+type splitResult struct {
+	DocumentID string `json:"documentId"`
+	Section    string `json:"section"`
+	Title      string `json:"title"`
+}
+
+func initSplitCommands() {
+	extractSectionCmd.Flags().String("folder", "", "Folder ID to create the new document in")
+	extractSectionCmd.Flags().Bool("include-heading", false, "Copy the section heading into the new document")
+	extractSectionCmd.Flags().Bool("link", false, "Replace the section content with a link to the new document")
+	extractSectionCmd.Flags().String("to-new", "", "Title of the new document (defaults to the section title)")
+	splitCmd.Flags().String("folder", "", "Folder ID to create the documents in")
+	splitCmd.Flags().Bool("include-heading", false, "Copy each section heading into its document")
+	splitCmd.Flags().Int("level", 1, "Heading level of the sections to split on (1-6)")
+}
+
+func runExtractSection(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	documentID := args[0]
+	sectionName := args[1]
+
+	folderID, _ := cmd.Flags().GetString("folder")
+	includeHeading, _ := cmd.Flags().GetBool("include-heading")
+	link, _ := cmd.Flags().GetBool("link")
+	title, _ := cmd.Flags().GetString("to-new")
+
+	doc, section, err := getSection(documentID, sectionName)
+	if err != nil {
+		return err
+	}
+
+	if title == "" {
+		title = section.Title
+	}
+
+	created, err := copySectionToNew(ctx, doc, section, title, folderID, includeHeading)
+	if err != nil {
+		if created != nil {
+			fmt.Fprintf(os.Stderr, "%s\n", red("⚠️  Document created without the section content: "+documentURL(created.DocumentId)))
+		}
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "%s\n", green("✅ Section '"+section.Title+"' extracted to: "+created.Title))
+	fmt.Fprintf(os.Stderr, "%s\n", green("   ID: "+created.DocumentId))
+
+	if link {
+		if err := batchUpdate(documentID, linkSectionRequests(doc, section, title, documentURL(created.DocumentId))); err != nil {
+			return fmt.Errorf("error replacing section with link: %w", err)
+		}
+		fmt.Fprintf(os.Stderr, "%s\n", green("✅ Section content replaced with a link"))
+	}
+
+	fmt.Println(created.DocumentId)
+	return nil
+}
+
+func runSplit(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	documentID := args[0]
+
+	folderID, _ := cmd.Flags().GetString("folder")
+	includeHeading, _ := cmd.Flags().GetBool("include-heading")
+	level, _ := cmd.Flags().GetInt("level")
+
+	if level < 1 || level > 6 {
+		return fmt.Errorf("invalid heading level: %d (must be 1-6)", level)
+	}
+
+	doc, err := getDocument(ctx, documentID)
+	if err != nil {
+		return err
+	}
+
+	var sections []document.Section
+	for _, section := range document.GetStructure(doc) {
+		if section.Level == level {
+			sections = append(sections, section)
+		}
+	}
+
+	if len(sections) == 0 {
+		return fmt.Errorf("no level %d headings in document", level)
+	}
+
+	results := []splitResult{}
+	for _, section := range sections {
+		created, err := copySectionToNew(ctx, doc, &section, section.Title, folderID, includeHeading)
+		if err != nil {
+			// List what was created so far, so the documents can be found or cleaned up
+			for _, result := range results {
+				fmt.Fprintf(os.Stderr, "%s\n", red("⚠️  Already created: "+result.Title+" "+documentURL(result.DocumentID)))
+			}
+			if created != nil {
+				fmt.Fprintf(os.Stderr, "%s\n", red("⚠️  Created without its content: "+created.Title+" "+documentURL(created.DocumentId)))
+			}
+			return fmt.Errorf("section '%s': %w", section.Title, err)
+		}
+
+		fmt.Fprintf(os.Stderr, "%s\n", green("✅ Created: "+created.Title))
+		results = append(results, splitResult{
+			DocumentID: created.DocumentId,
+			Section:    section.Path(),
+			Title:      created.Title,
+		})
+	}
+
+	return printJSON(results)
+}
+
+// copySectionToNew creates a document holding a copy of a section's content.
+// When copying fails after the document was created, it is returned with the error.
+func copySectionToNew(ctx context.Context, doc *docs.Document, section *document.Section, title, folderID string, includeHeading bool) (*docs.Document, error) {
+	startIndex := section.BodyStartIndex
+	if includeHeading {
+		startIndex = section.StartIndex
+	}
+
+	created, err := createDocument(ctx, title, folderID)
+	if err != nil {
+		return nil, err
+	}
+
+	elements := document.ElementsInRange(doc.Body.Content, startIndex, section.BodyEndIndex)
+	requests, _ := document.CopyElements(doc, elements, 1)
+	if len(requests) == 0 {
+		return created, nil
+	}
+
+	if err := batchUpdate(created.DocumentId, requests); err != nil {
+		return created, fmt.Errorf("error copying section content: %w", err)
+	}
+
+	return created, nil
+}
+
+// linkSectionRequests replace the body of a section with a paragraph linking to url
+func linkSectionRequests(doc *docs.Document, section *document.Section, text, url string) []*docs.Request {
+	var requests []*docs.Request
+
+	end := documentEnd(doc)
+	deleteEnd := min(section.BodyEndIndex, end-1)
+	if deleteEnd > section.BodyStartIndex {
+		requests = append(requests, deleteRangeRequest(section.BodyStartIndex, deleteEnd, end))
+		end -= deleteEnd - section.BodyStartIndex
+	}
+
+	index, openRequests := insertionIndex(section.BodyStartIndex, end)
+	requests = append(requests, openRequests...)
+
+	length := document.TextLength(text)
+	return append(requests,
+		&docs.Request{
+			InsertText: &docs.InsertTextRequest{
+				Location: &docs.Location{Index: index},
+				Text:     text + "\n",
+			},
+		},
+		&docs.Request{
+			UpdateParagraphStyle: &docs.UpdateParagraphStyleRequest{
+				Fields:         document.ParagraphStyleFields,
+				ParagraphStyle: &docs.ParagraphStyle{NamedStyleType: "NORMAL_TEXT"},
+				Range: &docs.Range{
+					EndIndex:   index + length + 1,
+					StartIndex: index,
+				},
+			},
+		},
+		&docs.Request{
+			UpdateTextStyle: &docs.UpdateTextStyleRequest{
+				Fields:    document.TextStyleFields,
+				Range:     &docs.Range{EndIndex: index + length, StartIndex: index},
+				TextStyle: &docs.TextStyle{Link: &docs.Link{Url: url}},
+			},
+		},
+	)
+}
+
+// documentURL returns the editor URL of a document
+func documentURL(documentID string) string {
+	return "https://docs.google.com/document/d/" + documentID + "/edit"
+}
@@ -0,0 +1,127 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"google-docs-manager/internal/document"
+
+	"github.com/spf13/cobra"
+	"google.golang.org/api/docs/v1"
+)
+
+var (
+	stylesCmd = &cobra.Command{
+		Short: "Read and apply a document's named styles (theme)",
+		Use:   "styles",
+	}
+
+	stylesApplyCmd = &cobra.Command{
+		Args:  cobra.RangeArgs(1, 2),
+		RunE:  runStylesApply,
+		Short: "Restyle every paragraph of each named style type to a theme file or reference document",
+		Use:   "apply <document-id> [<theme.yaml>]",
+	}
+
+	stylesGetCmd = &cobra.Command{
+		Args:  cobra.ExactArgs(1),
+		RunE:  runStylesGet,
+		Short: "Print the named styles of a document as YAML",
+		Use:   "get <document-id>",
+	}
+)
+
+func initStylesCommands() {
+	stylesApplyCmd.Flags().Bool("all-fields", false, "Apply every field of the theme, not only those differing from the document's named styles (overrides inline formatting)")
+	stylesApplyCmd.Flags().String("from", "", "Copy the theme of this reference document instead of reading a theme file")
+
+	stylesGetCmd.Flags().StringP("output", "o", "", "Write the YAML to this file instead of standard output")
+
+	stylesCmd.AddCommand(stylesApplyCmd)
+	stylesCmd.AddCommand(stylesGetCmd)
+}
+
+func runStylesGet(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	documentID := args[0]
+	output, _ := cmd.Flags().GetString("output")
+
+	doc, err := getDocument(ctx, documentID)
+	if err != nil {
+		return err
+	}
+
+	if doc.NamedStyles == nil {
+		return fmt.Errorf("document has no named styles")
+	}
+
+	data, err := document.MarshalNamedStyles(doc.NamedStyles)
+	if err != nil {
+		return fmt.Errorf("error encoding styles: %w", err)
+	}
+
+	if output == "" {
+		fmt.Print(string(data))
+		return nil
+	}
+
+	if err := os.WriteFile(output, data, exportFilePerm); err != nil {
+		return fmt.Errorf("error writing styles: %w", err)
+	}
+
+	fmt.Fprintf(os.Stderr, "%s\n", green("✅ Styles written to "+output))
+	return nil
+}
+
+func runStylesApply(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	documentID := args[0]
+
+	allFields, _ := cmd.Flags().GetBool("all-fields")
+	from, _ := cmd.Flags().GetString("from")
+
+	if (from == "") == (len(args) == 1) {
+		return fmt.Errorf("specify either a theme file or --from")
+	}
+
+	var theme *docs.NamedStyles
+	if from != "" {
+		reference, err := getDocument(ctx, from)
+		if err != nil {
+			return err
+		}
+		if reference.NamedStyles == nil {
+			return fmt.Errorf("reference document has no named styles")
+		}
+		theme = reference.NamedStyles
+	} else {
+		data, err := os.ReadFile(args[1])
+		if err != nil {
+			return fmt.Errorf("error reading theme: %w", err)
+		}
+
+		theme, err = document.ParseNamedStyles(data)
+		if err != nil {
+			return err
+		}
+	}
+
+	doc, err := getDocument(ctx, documentID)
+	if err != nil {
+		return err
+	}
+
+	requests := document.ThemeRequests(doc, theme, allFields)
+	if len(requests) == 0 {
+		fmt.Fprintf(os.Stderr, "%s\n", green("✅ Document already matches the theme"))
+		return nil
+	}
+
+	if err := batchUpdate(documentID, requests); err != nil {
+		return fmt.Errorf("error applying styles: %w", err)
+	}
+
+	fmt.Fprintf(os.Stderr, "%s\n", green("✅ Styles applied"))
+	return nil
+}
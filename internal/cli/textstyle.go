@@ -0,0 +1,224 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"google-docs-manager/internal/conversion"
+	"google-docs-manager/internal/document"
+
+	"github.com/spf13/cobra"
+	"google.golang.org/api/docs/v1"
+)
+
+// textStyleToggles are the on/off text style properties, each with a --no-
+// flag that explicitly turns it off
+var textStyleToggles = []struct {
+	field   string
+	flag    string
+	goField string
+	set     func(*docs.TextStyle, bool)
+	usage   string
+}{
+	{"bold", "bold", "Bold", func(s *docs.TextStyle, v bool) { s.Bold = v }, "bold"},
+	{"italic", "italic", "Italic", func(s *docs.TextStyle, v bool) { s.Italic = v }, "italic"},
+	{"smallCaps", "small-caps", "SmallCaps", func(s *docs.TextStyle, v bool) { s.SmallCaps = v }, "small caps"},
+	{"strikethrough", "strikethrough", "Strikethrough", func(s *docs.TextStyle, v bool) { s.Strikethrough = v }, "strikethrough"},
+	{"underline", "underline", "Underline", func(s *docs.TextStyle, v bool) { s.Underline = v }, "underlined"},
+}
+
+// addTextStyleFlags adds the text style flags to a command
+func addTextStyleFlags(cmd *cobra.Command) {
+	for _, toggle := range textStyleToggles {
+		cmd.Flags().Bool(toggle.flag, false, "Make text "+toggle.usage)
+		cmd.Flags().Bool("no-"+toggle.flag, false, "Make text not "+toggle.usage)
+	}
+
+	cmd.Flags().Bool("clear", false, "Reset the text to its paragraph's named style before applying other flags")
+	cmd.Flags().String("color", "", "Text color (hex, e.g., #FF0000)")
+	cmd.Flags().String("font", "", "Font family (e.g., Roboto)")
+	cmd.Flags().String("highlight", "", "Highlight (background) color (hex, e.g., #FFFF00)")
+	cmd.Flags().String("link", "", "Link to a URL, a heading (#<section> or #h.<heading-id>), or a bookmark (bookmark:<id>)")
+	cmd.Flags().Bool("no-baseline-offset", false, "Remove superscript or subscript")
+	cmd.Flags().Bool("no-color", false, "Remove the text color")
+	cmd.Flags().Bool("no-font", false, "Remove the font family and weight")
+	cmd.Flags().Bool("no-highlight", false, "Remove the highlight color")
+	cmd.Flags().Bool("no-link", false, "Remove links")
+	cmd.Flags().Bool("no-size", false, "Remove the font size")
+	cmd.Flags().Float64("size", 0, "Font size in points")
+	cmd.Flags().Bool("subscript", false, "Make text subscript")
+	cmd.Flags().Bool("superscript", false, "Make text superscript")
+	cmd.Flags().Int("weight", 0, "Font weight (100-900 in steps of 100, 400 is normal, 700 bold); requires --font")
+}
+
+// textStyleFlags builds a text style and its field mask from the text style
+// flags. The document is only fetched to resolve links to headings by name.
+func textStyleFlags(ctx context.Context, cmd *cobra.Command, documentID string) (*docs.TextStyle, []string, error) {
+	textStyle := &docs.TextStyle{}
+	fields := []string{}
+	flags := cmd.Flags()
+
+	reset, _ := flags.GetBool("clear")
+	if reset {
+		// Every field in the mask without a value reverts to the named style
+		fields = strings.Split(document.TextStyleFields, ",")
+	}
+
+	set := func(field string) {
+		if !slices.Contains(fields, field) {
+			fields = append(fields, field)
+		}
+	}
+
+	for _, toggle := range textStyleToggles {
+		on, _ := flags.GetBool(toggle.flag)
+		off, _ := flags.GetBool("no-" + toggle.flag)
+
+		if on && off {
+			return nil, nil, fmt.Errorf("--%s and --no-%s cannot be combined", toggle.flag, toggle.flag)
+		}
+		if on {
+			toggle.set(textStyle, true)
+			set(toggle.field)
+		}
+		if off {
+			// Explicitly false rather than inherited from the named style
+			toggle.set(textStyle, false)
+			textStyle.ForceSendFields = append(textStyle.ForceSendFields, toggle.goField)
+			set(toggle.field)
+		}
+	}
+
+	for _, color := range []struct {
+		field string
+		flag  string
+		set   func(*docs.OptionalColor)
+	}{
+		{"foregroundColor", "color", func(c *docs.OptionalColor) { textStyle.ForegroundColor = c }},
+		{"backgroundColor", "highlight", func(c *docs.OptionalColor) { textStyle.BackgroundColor = c }},
+	} {
+		value, _ := flags.GetString(color.flag)
+		remove, _ := flags.GetBool("no-" + color.flag)
+
+		if value != "" && remove {
+			return nil, nil, fmt.Errorf("--%s and --no-%s cannot be combined", color.flag, color.flag)
+		}
+		if value != "" {
+			parsed := conversion.ParseColor(value)
+			if parsed == nil {
+				return nil, nil, fmt.Errorf("invalid color: %s (must be a hex color like #FF0000)", value)
+			}
+			color.set(parsed)
+			set(color.field)
+		}
+		if remove {
+			set(color.field)
+		}
+	}
+
+	fontSize, _ := flags.GetFloat64("size")
+	noSize, _ := flags.GetBool("no-size")
+	if fontSize < 0 {
+		return nil, nil, fmt.Errorf("invalid font size: %g", fontSize)
+	}
+	if fontSize > 0 && noSize {
+		return nil, nil, fmt.Errorf("--size and --no-size cannot be combined")
+	}
+	if fontSize > 0 {
+		textStyle.FontSize = &docs.Dimension{
+			Magnitude: fontSize,
+			Unit:      "PT",
+		}
+		set("fontSize")
+	}
+	if noSize {
+		set("fontSize")
+	}
+
+	font, _ := flags.GetString("font")
+	weight, _ := flags.GetInt("weight")
+	noFont, _ := flags.GetBool("no-font")
+	if font != "" && noFont {
+		return nil, nil, fmt.Errorf("--font and --no-font cannot be combined")
+	}
+	if weight != 0 && (weight < 100 || weight > 900 || weight%100 != 0) {
+		return nil, nil, fmt.Errorf("invalid font weight: %d (must be 100-900 in steps of 100)", weight)
+	}
+	if weight != 0 && font == "" {
+		return nil, nil, fmt.Errorf("--weight requires --font")
+	}
+	if font != "" {
+		textStyle.WeightedFontFamily = &docs.WeightedFontFamily{FontFamily: font, Weight: int64(weight)}
+		set("weightedFontFamily")
+	}
+	if noFont {
+		set("weightedFontFamily")
+	}
+
+	superscript, _ := flags.GetBool("superscript")
+	subscript, _ := flags.GetBool("subscript")
+	noBaselineOffset, _ := flags.GetBool("no-baseline-offset")
+	switch {
+	case superscript && subscript:
+		return nil, nil, fmt.Errorf("--superscript and --subscript cannot be combined")
+	case (superscript || subscript) && noBaselineOffset:
+		return nil, nil, fmt.Errorf("--superscript and --subscript cannot be combined with --no-baseline-offset")
+	case superscript:
+		textStyle.BaselineOffset = "SUPERSCRIPT"
+		set("baselineOffset")
+	case subscript:
+		textStyle.BaselineOffset = "SUBSCRIPT"
+		set("baselineOffset")
+	case noBaselineOffset:
+		textStyle.BaselineOffset = "NONE"
+		set("baselineOffset")
+	}
+
+	link, _ := flags.GetString("link")
+	noLink, _ := flags.GetBool("no-link")
+	if link != "" && noLink {
+		return nil, nil, fmt.Errorf("--link and --no-link cannot be combined")
+	}
+	if link != "" {
+		parsed, err := parseLink(ctx, link, documentID)
+		if err != nil {
+			return nil, nil, err
+		}
+		textStyle.Link = parsed
+		set("link")
+	}
+	if noLink {
+		set("link")
+	}
+
+	return textStyle, fields, nil
+}
+
+// parseLink parses a --link value: #<section selector> or #h.<id> links to a
+// heading, bookmark:<id> to a bookmark, and anything else is a URL
+func parseLink(ctx context.Context, link, documentID string) (*docs.Link, error) {
+	switch {
+	case strings.HasPrefix(link, "bookmark:"):
+		return &docs.Link{BookmarkId: strings.TrimPrefix(link, "bookmark:")}, nil
+	case strings.HasPrefix(link, "#h."):
+		return &docs.Link{HeadingId: strings.TrimPrefix(link, "#")}, nil
+	case strings.HasPrefix(link, "#"):
+		doc, err := getDocument(ctx, documentID)
+		if err != nil {
+			return nil, err
+		}
+
+		section, err := document.FindSection(doc, strings.TrimPrefix(link, "#"))
+		if err != nil {
+			return nil, err
+		}
+		if section.HeadingID == "" {
+			return nil, fmt.Errorf("heading '%s' has no ID to link to", section.Title)
+		}
+		return &docs.Link{HeadingId: section.HeadingID}, nil
+	}
+
+	return &docs.Link{Url: link}, nil
+}
@@ -0,0 +1,154 @@
+package conversion
+
+import (
+	"fmt"
+	"strings"
+)
+
+// asciidocRenderer renders AsciiDoc, using unconstrained inline formatting so
+// emphasis also works inside words
+type asciidocRenderer struct {
+	// book is set when TITLE paragraphs follow content, which makes them level 0
+	// sections; AsciiDoc only allows those in books
+	book   bool
+	inList bool
+	out    strings.Builder
+	title  string
+	titled bool
+}
+
+// Begin records the title; the header is written by End, once it is known
+// whether a TITLE paragraph replaces it
+func (r *asciidocRenderer) Begin(title string) {
+	r.title = title
+}
+
+func (r *asciidocRenderer) block() {
+	if r.inList {
+		r.out.WriteString("\n")
+		r.inList = false
+	}
+}
+
+// Heading maps TITLE to the document title, or to a level 0 section once
+// content precedes it, and HEADING_n to a level n section. AsciiDoc stops at
+// level 5, so HEADING_6 becomes a discrete heading of that level.
+func (r *asciidocRenderer) Heading(level int, id string, text string) {
+	r.block()
+	if level == 0 && r.out.Len() == 0 && !r.titled {
+		r.title, r.titled = text, true
+		return
+	}
+
+	if id != "" {
+		r.out.WriteString(fmt.Sprintf("[[%s]]\n", id))
+	}
+	if level == 0 {
+		r.book = true
+	}
+	if level > 5 {
+		r.out.WriteString("[discrete]\n")
+	}
+	r.out.WriteString(fmt.Sprintf("%s %s\n\n", strings.Repeat("=", min(level, 5)+1), text))
+}
+
+func (r *asciidocRenderer) Paragraph(text string) {
+	r.block()
+	r.out.WriteString(text + "\n\n")
+}
+
+func (r *asciidocRenderer) ListItem(level int, ordered bool, text string) {
+	marker := "*"
+	if ordered {
+		marker = "."
+	}
+	r.out.WriteString(fmt.Sprintf("%s %s\n", strings.Repeat(marker, level+1), text))
+	r.inList = true
+}
+
+func (r *asciidocRenderer) Table(table Table) {
+	r.block()
+	r.out.WriteString("[options=\"header\"]\n|===\n")
+	for rowIdx, row := range table.Rows {
+		for _, cell := range row {
+			r.out.WriteString("|" + strings.ReplaceAll(cell, "|", "\\|") + " ")
+		}
+		r.out.WriteString("\n")
+		if rowIdx == 0 {
+			r.out.WriteString("\n")
+		}
+	}
+	r.out.WriteString("|===\n\n")
+}
+
+func (r *asciidocRenderer) CodeBlock(lines []string) {
+	r.block()
+	r.out.WriteString("[source]\n----\n" + strings.Join(lines, "\n") + "\n----\n\n")
+}
+
+func (r *asciidocRenderer) Break() {
+	r.block()
+	r.out.WriteString("'''\n\n")
+}
+
+func (r *asciidocRenderer) TableOfContents() {
+	r.block()
+	r.out.WriteString("toc::[]\n\n")
+}
+
+// Footnote is a no-op: AsciiDoc footnotes are written inline
+func (r *asciidocRenderer) Footnote(id string, number string, text string) {}
+
+func (r *asciidocRenderer) Inline(inline Inline) string {
+	switch inline.Kind {
+	case InlineImage:
+		return fmt.Sprintf("image:%s[%s]", inline.Path, strings.ReplaceAll(inline.Alt, "]", "\\]"))
+	case InlineFootnote:
+		return fmt.Sprintf("footnote:[%s]", strings.ReplaceAll(inline.Text, "]", "\\]"))
+	case InlineEquation:
+		return "+" + inline.Text + "+"
+	case InlineRule:
+		return "'''"
+	}
+
+	content := inline.Text
+	if inline.Code {
+		content = wrapInline("``", content, "``")
+	}
+	if inline.Bold {
+		content = wrapInline("**", content, "**")
+	}
+	if inline.Italic {
+		content = wrapInline("__", content, "__")
+	}
+	if inline.Underline {
+		content = wrapInline("[.underline]#", content, "#")
+	}
+	if inline.Strikethrough {
+		content = wrapInline("[.line-through]#", content, "#")
+	}
+	if inline.Baseline == "SUPERSCRIPT" {
+		content = wrapInline("^", content, "^")
+	} else if inline.Baseline == "SUBSCRIPT" {
+		content = wrapInline("~", content, "~")
+	}
+	if inline.Link != "" {
+		if strings.HasPrefix(inline.Link, "#") {
+			content = wrapInline("<<"+strings.TrimPrefix(inline.Link, "#")+",", content, ">>")
+		} else {
+			content = wrapInline(inline.Link+"[", content, "]")
+		}
+	}
+
+	return content
+}
+
+func (r *asciidocRenderer) End() string {
+	r.block()
+
+	header := fmt.Sprintf("= %s\n", r.title)
+	if r.book {
+		header += ":doctype: book\n"
+	}
+	return header + "\n" + r.out.String()
+}
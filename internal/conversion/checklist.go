@@ -0,0 +1,53 @@
+package conversion
+
+import (
+	"strings"
+
+	"google.golang.org/api/docs/v1"
+)
+
+// IsChecklist reports whether a bullet's nesting level uses checkboxes, which
+// have neither a numbered glyph type nor a glyph symbol
+func IsChecklist(doc *docs.Document, bullet *docs.Bullet) bool {
+	list, ok := doc.Lists[bullet.ListId]
+	if !ok || list.ListProperties == nil {
+		return false
+	}
+
+	levels := list.ListProperties.NestingLevels
+	if int(bullet.NestingLevel) >= len(levels) {
+		return false
+	}
+
+	level := levels[bullet.NestingLevel]
+	return !IsOrderedList(doc, bullet) && level.GlyphSymbol == ""
+}
+
+// IsChecked reports whether a checklist item is checked. The API does not
+// expose the checkbox state, so it is inferred from the strikethrough Docs
+// applies to the text of checked items.
+func IsChecked(paragraph *docs.Paragraph) bool {
+	if paragraph.Bullet != nil && paragraph.Bullet.TextStyle != nil && paragraph.Bullet.TextStyle.Strikethrough {
+		return true
+	}
+
+	struck := false
+	for _, element := range paragraph.Elements {
+		if element.TextRun == nil || strings.TrimSpace(element.TextRun.Content) == "" {
+			continue
+		}
+		if element.TextRun.TextStyle == nil || !element.TextRun.TextStyle.Strikethrough {
+			return false
+		}
+		struck = true
+	}
+	return struck
+}
+
+// checkboxMarker renders a checklist item's checkbox, as in markdown task lists
+func checkboxMarker(checked bool) string {
+	if checked {
+		return "[x] "
+	}
+	return "[ ] "
+}
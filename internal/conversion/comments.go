@@ -0,0 +1,175 @@
+package conversion
+
+import (
+	"strings"
+	"unicode/utf16"
+
+	"google.golang.org/api/docs/v1"
+)
+
+// commentAnnotator is implemented by renderers that mark the text comments are
+// anchored to. Annotate wraps a rendered piece of that text; last is set on the
+// final piece of a comment spanning several paragraphs. Comments whose anchor
+// was not found are passed with empty text and are skipped when the result is
+// empty too.
+type commentAnnotator interface {
+	Annotate(comment Comment, text string, last bool) string
+}
+
+// commentAnchor is a comment resolved to the body range it is anchored to
+type commentAnchor struct {
+	comment    Comment
+	done       bool
+	endIndex   int64
+	startIndex int64
+}
+
+// bodyRune is a character of the body text along with its document index
+type bodyRune struct {
+	index int64
+	r     rune
+}
+
+// resolveComments locates the quoted text of each comment in the body, tables
+// included. Drive only exposes the quoted text of Docs comments, so comments
+// quoting the same text take its occurrences in turn; anchors never overlap.
+// Comments whose text is not found get an empty range.
+func resolveComments(doc *docs.Document, comments []Comment) []*commentAnchor {
+	var text []bodyRune
+	if doc.Body != nil {
+		text = bodyText(doc.Body.Content, nil)
+	}
+
+	anchors := make([]*commentAnchor, 0, len(comments))
+	for _, comment := range comments {
+		anchor := &commentAnchor{comment: comment, endIndex: -1, startIndex: -1}
+		anchors = append(anchors, anchor)
+
+		quoted := []rune(strings.TrimSpace(comment.Quoted))
+		if len(quoted) == 0 {
+			continue
+		}
+
+		for i := 0; i+len(quoted) <= len(text); i++ {
+			if !runesAt(text, i, quoted) {
+				continue
+			}
+
+			lastRune := text[i+len(quoted)-1]
+			start, end := text[i].index, lastRune.index+int64(utf16.RuneLen(lastRune.r))
+			if overlapsAnchor(anchors, start, end) {
+				continue
+			}
+
+			anchor.startIndex, anchor.endIndex = start, end
+			break
+		}
+	}
+
+	return anchors
+}
+
+// bodyText appends the characters of a body's paragraphs to text, with
+// U+FFFC standing for chips, images and other non-text elements
+func bodyText(content []*docs.StructuralElement, text []bodyRune) []bodyRune {
+	for _, element := range content {
+		switch {
+		case element.Paragraph != nil:
+			for _, e := range element.Paragraph.Elements {
+				if e.TextRun == nil {
+					text = append(text, bodyRune{index: e.StartIndex, r: '\uFFFC'})
+					continue
+				}
+
+				index := e.StartIndex
+				for _, r := range e.TextRun.Content {
+					text = append(text, bodyRune{index: index, r: r})
+					index += int64(utf16.RuneLen(r))
+				}
+			}
+		case element.Table != nil:
+			for _, row := range element.Table.TableRows {
+				for _, cell := range row.TableCells {
+					text = bodyText(cell.Content, text)
+				}
+			}
+		}
+	}
+	return text
+}
+
+func runesAt(text []bodyRune, i int, runes []rune) bool {
+	for j, r := range runes {
+		if text[i+j].r != r {
+			return false
+		}
+	}
+	return true
+}
+
+func overlapsAnchor(anchors []*commentAnchor, start int64, end int64) bool {
+	for _, anchor := range anchors {
+		if anchor.startIndex < end && start < anchor.endIndex {
+			return true
+		}
+	}
+	return false
+}
+
+// anchorAt returns the anchor covering a piece of text, if any
+func anchorAt(anchors []*commentAnchor, start int64, end int64) *commentAnchor {
+	for _, anchor := range anchors {
+		if anchor.startIndex <= start && end <= anchor.endIndex {
+			return anchor
+		}
+	}
+	return nil
+}
+
+// textPiece is a part of a text run between comment anchor boundaries
+type textPiece struct {
+	end   int64
+	start int64
+	text  string
+}
+
+// splitAtAnchors cuts text starting at a document index wherever a comment
+// anchor starts or ends, so every piece is either inside or outside an anchor
+func splitAtAnchors(text string, start int64, anchors []*commentAnchor) []textPiece {
+	var pieces []textPiece
+	var current strings.Builder
+
+	pieceStart := start
+	index := start
+	for _, r := range text {
+		if index > pieceStart && isAnchorBoundary(anchors, index) {
+			pieces = append(pieces, textPiece{end: index, start: pieceStart, text: current.String()})
+			current.Reset()
+			pieceStart = index
+		}
+		current.WriteRune(r)
+		index += int64(utf16.RuneLen(r))
+	}
+
+	if current.Len() > 0 {
+		pieces = append(pieces, textPiece{end: index, start: pieceStart, text: current.String()})
+	}
+	return pieces
+}
+
+func isAnchorBoundary(anchors []*commentAnchor, index int64) bool {
+	for _, anchor := range anchors {
+		if anchor.startIndex >= 0 && (anchor.startIndex == index || anchor.endIndex == index) {
+			return true
+		}
+	}
+	return false
+}
+
+func commentText(comment Comment) string {
+	content := strings.Join(strings.Fields(comment.Content), " ")
+	if comment.Author == "" {
+		return content
+	}
+	return comment.Author + ": " + content
+}
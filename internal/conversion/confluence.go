@@ -0,0 +1,80 @@
+package conversion
+
+import (
+	"fmt"
+	"html"
+	"path/filepath"
+	"strings"
+)
+
+// confluenceRenderer renders Confluence storage format: XHTML with ac: macros
+// for code blocks, callouts and the table of contents. Images reference page
+// attachments by file name.
+type confluenceRenderer struct {
+	*htmlRenderer
+}
+
+func newConfluenceRenderer() *confluenceRenderer {
+	return &confluenceRenderer{htmlRenderer: newHTMLRenderer()}
+}
+
+// Begin omits the title, which Confluence stores as the page title
+func (r *confluenceRenderer) Begin(title string) {
+	r.title = title
+}
+
+func (r *confluenceRenderer) Table(table Table) {
+	if !table.Callout {
+		r.htmlRenderer.Table(table)
+		return
+	}
+
+	r.closeLists(-1)
+	r.out.WriteString("<ac:structured-macro ac:name=\"info\"><ac:rich-text-body>")
+	r.out.WriteString(fmt.Sprintf("<p>%s</p>", table.Rows[0][0]))
+	r.out.WriteString("</ac:rich-text-body></ac:structured-macro>\n")
+}
+
+func (r *confluenceRenderer) CodeBlock(lines []string) {
+	r.closeLists(-1)
+	code := strings.ReplaceAll(strings.Join(lines, "\n"), "]]>", "]]]]><![CDATA[>")
+	r.out.WriteString("<ac:structured-macro ac:name=\"code\"><ac:plain-text-body><![CDATA[" + code + "]]></ac:plain-text-body></ac:structured-macro>\n")
+}
+
+func (r *confluenceRenderer) Break() {
+	r.closeLists(-1)
+	r.out.WriteString("<hr />\n")
+}
+
+func (r *confluenceRenderer) TableOfContents() {
+	r.closeLists(-1)
+	r.out.WriteString("<ac:structured-macro ac:name=\"toc\" />\n")
+}
+
+func (r *confluenceRenderer) Inline(inline Inline) string {
+	switch inline.Kind {
+	case InlineImage:
+		return fmt.Sprintf("<ac:image ac:alt=\"%s\"><ri:attachment ri:filename=\"%s\" /></ac:image>",
+			html.EscapeString(inline.Alt), html.EscapeString(filepath.Base(inline.Path)))
+	case InlineRule:
+		return "<hr />"
+	}
+	return r.htmlRenderer.Inline(inline)
+}
+
+// Annotate leaves commented text unmarked: storage format has no inline
+// annotation, and inline comment markers must reference an existing Confluence
+// comment
+func (r *confluenceRenderer) Annotate(comment Comment, text string, last bool) string {
+	return text
+}
+
+func (r *confluenceRenderer) End() string {
+	r.closeLists(-1)
+
+	if len(r.footnotes) > 0 {
+		r.out.WriteString("<ol>\n" + strings.Join(r.footnotes, "\n") + "\n</ol>\n")
+	}
+
+	return r.out.String()
+}
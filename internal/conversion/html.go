@@ -0,0 +1,573 @@
+package conversion
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"regexp"
+	"strings"
+	"unicode/utf16"
+
+	nethtml "golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+	"google.golang.org/api/docs/v1"
+)
+
+var (
+	slugRegex = regexp.MustCompile(`[^a-z0-9]+`)
+	tagRegex  = regexp.MustCompile(`<[^>]*>`)
+)
+
+// DocsToHTML converts a Google Doc to a sanitized HTML5 document
+func DocsToHTML(doc *docs.Document, opts RenderOptions) string {
+	return Render(doc, newHTMLRenderer(), opts)
+}
+
+// htmlRenderer renders sanitized HTML5: all text is escaped and only links
+// with safe schemes are kept
+type htmlRenderer struct {
+	footnotes []string
+	ids       map[string]int
+	lists     []string
+	out       strings.Builder
+	title     string
+}
+
+func newHTMLRenderer() *htmlRenderer {
+	return &htmlRenderer{ids: map[string]int{}}
+}
+
+func (r *htmlRenderer) Begin(title string) {
+	r.title = title
+	r.out.WriteString(fmt.Sprintf("<h1>%s</h1>\n", html.EscapeString(title)))
+}
+
+func (r *htmlRenderer) Heading(level int, id string, text string) {
+	r.closeLists(-1)
+	tag := fmt.Sprintf("h%d", min(level+1, 6))
+	r.out.WriteString(fmt.Sprintf("<%s id=\"%s\">%s</%s>\n", tag, html.EscapeString(r.headingID(id, text)), text, tag))
+}
+
+func (r *htmlRenderer) Paragraph(text string) {
+	r.closeLists(-1)
+	r.out.WriteString(fmt.Sprintf("<p>%s</p>\n", text))
+}
+
+func (r *htmlRenderer) ListItem(level int, ordered bool, text string) {
+	tag := "ul"
+	if ordered {
+		tag = "ol"
+	}
+	r.openListItem(level, tag)
+	r.out.WriteString("<li>" + text)
+}
+
+func (r *htmlRenderer) Table(table Table) {
+	r.closeLists(-1)
+	r.out.WriteString("<table>\n")
+	for rowIdx, row := range table.Rows {
+		cellTag := "td"
+		if rowIdx == 0 {
+			cellTag = "th"
+		}
+
+		r.out.WriteString("<tr>")
+		for _, cell := range row {
+			r.out.WriteString(fmt.Sprintf("<%s>%s</%s>", cellTag, cell, cellTag))
+		}
+		r.out.WriteString("</tr>\n")
+	}
+	r.out.WriteString("</table>\n")
+}
+
+func (r *htmlRenderer) CodeBlock(lines []string) {
+	r.closeLists(-1)
+	r.out.WriteString(fmt.Sprintf("<pre><code>%s</code></pre>\n", html.EscapeString(strings.Join(lines, "\n"))))
+}
+
+func (r *htmlRenderer) Break() {
+	r.closeLists(-1)
+	r.out.WriteString("<hr>\n")
+}
+
+func (r *htmlRenderer) TableOfContents() {
+	r.closeLists(-1)
+	r.out.WriteString("<nav class=\"toc\"></nav>\n")
+}
+
+func (r *htmlRenderer) Footnote(id string, number string, text string) {
+	r.footnotes = append(r.footnotes, fmt.Sprintf("<li id=\"fn-%s\">%s <a href=\"#fnref-%s\">↩</a></li>",
+		html.EscapeString(id), text, html.EscapeString(id)))
+}
+
+func (r *htmlRenderer) Inline(inline Inline) string {
+	switch inline.Kind {
+	case InlineImage:
+		return fmt.Sprintf("<img src=\"%s\" alt=\"%s\">", html.EscapeString(inline.Path), html.EscapeString(inline.Alt))
+	case InlineFootnote:
+		return fmt.Sprintf("<sup id=\"fnref-%s\"><a href=\"#fn-%s\">%s</a></sup>",
+			html.EscapeString(inline.FootnoteID), html.EscapeString(inline.FootnoteID), html.EscapeString(inline.FootnoteNumber))
+	case InlineEquation:
+		return fmt.Sprintf("<code class=\"equation\">%s</code>", html.EscapeString(inline.Text))
+	case InlineRule:
+		return "<hr>"
+	}
+
+	content := html.EscapeString(inline.Text)
+	if inline.Bold {
+		content = "<strong>" + content + "</strong>"
+	}
+	if inline.Italic {
+		content = "<em>" + content + "</em>"
+	}
+	if inline.Underline {
+		content = "<u>" + content + "</u>"
+	}
+	if inline.Strikethrough {
+		content = "<s>" + content + "</s>"
+	}
+	if inline.Baseline == "SUPERSCRIPT" {
+		content = "<sup>" + content + "</sup>"
+	} else if inline.Baseline == "SUBSCRIPT" {
+		content = "<sub>" + content + "</sub>"
+	}
+	if inline.Code {
+		content = "<code>" + content + "</code>"
+	}
+	if inline.Link != "" {
+		content = linkHTML(inline.Link, content)
+	}
+	if inline.Deleted {
+		content = "<del>" + content + "</del>"
+	}
+	if inline.Inserted {
+		content = "<ins>" + content + "</ins>"
+	}
+
+	return content
+}
+
+func (r *htmlRenderer) End() string {
+	r.closeLists(-1)
+
+	if len(r.footnotes) > 0 {
+		r.out.WriteString("<section class=\"footnotes\">\n<ol>\n" + strings.Join(r.footnotes, "\n") + "\n</ol>\n</section>\n")
+	}
+
+	var out strings.Builder
+	out.WriteString("<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n")
+	out.WriteString(fmt.Sprintf("<title>%s</title>\n", html.EscapeString(r.title)))
+	out.WriteString("</head>\n<body>\n")
+	out.WriteString(r.out.String())
+	out.WriteString("</body>\n</html>\n")
+
+	return out.String()
+}
+
+// Annotate highlights commented text with a <mark> titled with the comment.
+// Comments whose text was not found are left out.
+func (r *htmlRenderer) Annotate(comment Comment, text string, last bool) string {
+	if text == "" {
+		return ""
+	}
+	return fmt.Sprintf("<mark title=\"%s\">%s</mark>", html.EscapeString(commentText(comment)), text)
+}
+
+// headingID returns a unique anchor for a heading, preferring the Docs heading ID
+func (r *htmlRenderer) headingID(docsID string, text string) string {
+	id := docsID
+	if id == "" {
+		id = slugify(text)
+	}
+
+	r.ids[id]++
+	if r.ids[id] > 1 {
+		return fmt.Sprintf("%s-%d", id, r.ids[id])
+	}
+	return id
+}
+
+// openListItem prepares the list stack for a new item at level, closing the
+// previous item and opening nested lists inside the parent item as needed
+func (r *htmlRenderer) openListItem(level int, tag string) {
+	r.closeLists(level)
+
+	if len(r.lists) == level+1 {
+		if r.lists[level] == tag {
+			r.out.WriteString("</li>\n")
+		} else {
+			r.closeLists(level - 1)
+		}
+	}
+
+	for len(r.lists) <= level {
+		r.lists = append(r.lists, tag)
+		r.out.WriteString("<" + tag + ">\n")
+	}
+}
+
+// closeLists closes open lists deeper than level (-1 closes all of them)
+func (r *htmlRenderer) closeLists(level int) {
+	for len(r.lists) > level+1 {
+		tag := r.lists[len(r.lists)-1]
+		r.lists = r.lists[:len(r.lists)-1]
+		r.out.WriteString("</li>\n</" + tag + ">\n")
+	}
+}
+
+// slugify turns heading text (possibly containing markup) into an anchor name
+func slugify(text string) string {
+	plain := html.UnescapeString(tagRegex.ReplaceAllString(text, ""))
+	slug := strings.Trim(slugRegex.ReplaceAllString(strings.ToLower(plain), "-"), "-")
+	if slug == "" {
+		return "section"
+	}
+	return slug
+}
+
+// IsMonospace reports whether a text style uses a common code font
+func IsMonospace(style *docs.TextStyle) bool {
+	if style.WeightedFontFamily == nil {
+		return false
+	}
+
+	switch style.WeightedFontFamily.FontFamily {
+	case "Courier New", "Consolas", "Roboto Mono", "Source Code Pro", "Inconsolata":
+		return true
+	}
+	return false
+}
+
+// LinkTarget returns the URL of a link, or a fragment for heading and bookmark links
+func LinkTarget(link *docs.Link) string {
+	switch {
+	case link.Url != "":
+		return link.Url
+	case link.HeadingId != "":
+		return "#" + link.HeadingId
+	case link.Heading != nil && link.Heading.Id != "":
+		return "#" + link.Heading.Id
+	case link.BookmarkId != "":
+		return "#" + link.BookmarkId
+	case link.Bookmark != nil && link.Bookmark.Id != "":
+		return "#" + link.Bookmark.Id
+	}
+	return ""
+}
+
+// linkHTML renders an anchor, dropping the link when its scheme is not safe
+func linkHTML(target string, inner string) string {
+	lower := strings.ToLower(strings.TrimSpace(target))
+	safe := strings.HasPrefix(lower, "http://") || strings.HasPrefix(lower, "https://") ||
+		strings.HasPrefix(lower, "mailto:") || strings.HasPrefix(lower, "#")
+	if !safe {
+		return inner
+	}
+	return fmt.Sprintf("<a href=\"%s\">%s</a>", html.EscapeString(target), inner)
+}
+
+// htmlBlock is a paragraph of an HTML document, written as a line of the
+// markdown dialect understood by MarkdownToDocsRequests with its text escaped.
+// List items keep their nesting level and list type, since that dialect has no
+// lists; code block lines are kept verbatim.
+type htmlBlock struct {
+	code    bool
+	level   int
+	list    bool
+	ordered bool
+	text    string
+}
+
+// htmlListItem is a list item inserted between two indices
+type htmlListItem struct {
+	htmlBlock
+	end   int64
+	start int64
+}
+
+// HTMLToDocsRequests converts an HTML document to Docs API requests inserting
+// it at startIndex. Paragraph text goes through the markdown converter, lists
+// become Docs lists and preformatted text becomes monospace lines. Scripts,
+// styles and unknown elements are dropped; tables are not supported and return
+// an error rather than degrading to text.
+func HTMLToDocsRequests(r io.Reader, startIndex int64, opts MarkdownOptions) ([]*docs.Request, error) {
+	root, err := nethtml.Parse(r)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing HTML: %w", err)
+	}
+
+	var blocks []htmlBlock
+	if err := collectHTMLBlocks(root, &blocks); err != nil {
+		return nil, err
+	}
+
+	var requests []*docs.Request
+	var items []htmlListItem
+
+	index := startIndex
+	for _, block := range blocks {
+		if block.code {
+			blockRequests, end := codeLineRequests(block.text, index)
+			requests = append(requests, blockRequests...)
+			index = end
+			continue
+		}
+
+		blockRequests, end := markdownRequests(block.text, index, opts)
+		requests = append(requests, blockRequests...)
+		if block.list {
+			items = append(items, htmlListItem{end: end, htmlBlock: block, start: index})
+		}
+		index = end
+	}
+
+	return append(requests, htmlListRequests(items)...), nil
+}
+
+func collectHTMLBlocks(node *nethtml.Node, blocks *[]htmlBlock) error {
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		if child.Type != nethtml.ElementNode {
+			if child.Type == nethtml.TextNode && strings.TrimSpace(child.Data) != "" && node.DataAtom == atom.Body {
+				*blocks = append(*blocks, htmlBlock{text: collapseSpace(escapeMarkdown(child.Data))}, htmlBlock{})
+			}
+			continue
+		}
+
+		switch child.DataAtom {
+		case atom.Head, atom.Script, atom.Style, atom.Template, atom.Noscript:
+			continue
+		case atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6:
+			level := int(child.Data[1] - '0')
+			*blocks = append(*blocks, htmlBlock{text: strings.Repeat("#", level) + " " + strings.TrimSpace(inlineMarkdown(child))}, htmlBlock{})
+		case atom.Pre:
+			for _, line := range strings.Split(strings.TrimSuffix(textContent(child), "\n"), "\n") {
+				*blocks = append(*blocks, htmlBlock{code: true, text: line})
+			}
+			*blocks = append(*blocks, htmlBlock{})
+		case atom.P, atom.Blockquote, atom.Figcaption:
+			if text := strings.TrimSpace(inlineMarkdown(child)); text != "" {
+				*blocks = append(*blocks, htmlBlock{text: text}, htmlBlock{})
+			}
+		case atom.Ul, atom.Ol:
+			collectHTMLList(child, blocks, 0)
+			*blocks = append(*blocks, htmlBlock{})
+		case atom.Table:
+			return fmt.Errorf("unsupported HTML: tables cannot be inserted from HTML, import the file as a new document instead")
+		case atom.Hr:
+			*blocks = append(*blocks, htmlBlock{text: breakMarker}, htmlBlock{})
+		default:
+			if err := collectHTMLBlocks(child, blocks); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func collectHTMLList(list *nethtml.Node, blocks *[]htmlBlock, level int) {
+	for item := list.FirstChild; item != nil; item = item.NextSibling {
+		if item.Type != nethtml.ElementNode || item.DataAtom != atom.Li {
+			continue
+		}
+
+		var nested []*nethtml.Node
+		var text strings.Builder
+		for child := item.FirstChild; child != nil; child = child.NextSibling {
+			if child.Type == nethtml.ElementNode && (child.DataAtom == atom.Ul || child.DataAtom == atom.Ol) {
+				nested = append(nested, child)
+				continue
+			}
+			text.WriteString(inlineNodeMarkdown(child))
+		}
+
+		*blocks = append(*blocks, htmlBlock{
+			level:   level,
+			list:    true,
+			ordered: list.DataAtom == atom.Ol,
+			text:    strings.TrimSpace(collapseSpace(text.String())),
+		})
+		for _, n := range nested {
+			collectHTMLList(n, blocks, level+1)
+		}
+	}
+}
+
+// htmlListRequests turn runs of adjacent list items into Docs lists, numbered
+// when the outermost list is ordered. Nesting is set through leading tabs, which
+// CreateParagraphBullets removes, so runs are built from the last one to keep
+// earlier indices valid.
+func htmlListRequests(items []htmlListItem) []*docs.Request {
+	var runs [][]htmlListItem
+	for _, item := range items {
+		if n := len(runs); n > 0 && runs[n-1][len(runs[n-1])-1].end == item.start {
+			runs[n-1] = append(runs[n-1], item)
+			continue
+		}
+		runs = append(runs, []htmlListItem{item})
+	}
+
+	var requests []*docs.Request
+	for i := len(runs) - 1; i >= 0; i-- {
+		run := runs[i]
+
+		tabs := int64(0)
+		for j := len(run) - 1; j >= 0; j-- {
+			if run[j].level == 0 {
+				continue
+			}
+			requests = append(requests, &docs.Request{
+				InsertText: &docs.InsertTextRequest{
+					Location: &docs.Location{Index: run[j].start},
+					Text:     strings.Repeat("\t", run[j].level),
+				},
+			})
+			tabs += int64(run[j].level)
+		}
+
+		preset := "BULLET_DISC_CIRCLE_SQUARE"
+		if run[0].ordered {
+			preset = "NUMBERED_DECIMAL_ALPHA_ROMAN"
+		}
+
+		requests = append(requests, &docs.Request{
+			CreateParagraphBullets: &docs.CreateParagraphBulletsRequest{
+				BulletPreset: preset,
+				Range: &docs.Range{
+					EndIndex:   run[len(run)-1].end + tabs,
+					StartIndex: run[0].start,
+				},
+			},
+		})
+	}
+
+	return requests
+}
+
+// codeLineRequests inserts a line of preformatted text as a paragraph in the
+// code font, returning the index right after it
+func codeLineRequests(line string, index int64) ([]*docs.Request, int64) {
+	end := index + int64(len(utf16.Encode([]rune(line))))
+	requests := []*docs.Request{
+		{InsertText: &docs.InsertTextRequest{Location: &docs.Location{Index: index}, Text: line + "\n"}},
+		normalTextRequest(index, end+1),
+	}
+
+	if end > index {
+		requests = append(requests, &docs.Request{
+			UpdateTextStyle: &docs.UpdateTextStyleRequest{
+				Fields:    "weightedFontFamily",
+				Range:     &docs.Range{EndIndex: end, StartIndex: index},
+				TextStyle: codeTextStyle(),
+			},
+		})
+	}
+
+	return requests, end + 1
+}
+
+func inlineMarkdown(node *nethtml.Node) string {
+	var text strings.Builder
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		text.WriteString(inlineNodeMarkdown(child))
+	}
+	return collapseSpace(text.String())
+}
+
+func inlineNodeMarkdown(node *nethtml.Node) string {
+	if node.Type == nethtml.TextNode {
+		return escapeMarkdown(node.Data)
+	}
+	if node.Type != nethtml.ElementNode {
+		return ""
+	}
+
+	inner := inlineMarkdown(node)
+	switch node.DataAtom {
+	case atom.Script, atom.Style:
+		return ""
+	case atom.Strong, atom.B:
+		return wrapInline("**", inner, "**")
+	case atom.Em, atom.I:
+		return wrapInline("*", inner, "*")
+	case atom.Code, atom.Kbd, atom.Samp, atom.Tt:
+		return wrapInline("`", inner, "`")
+	case atom.S, atom.Del, atom.Strike:
+		return wrapInline("~~", inner, "~~")
+	case atom.A:
+		href := attr(node, "href")
+		if href == "" || strings.TrimSpace(inner) == "" {
+			return inner
+		}
+		return fmt.Sprintf("[%s](%s)", strings.TrimSpace(inner), markdownURL(href))
+	case atom.Br:
+		return " "
+	case atom.Img:
+		alt := escapeMarkdown(collapseSpace(attr(node, "alt")))
+		src := strings.TrimSpace(attr(node, "src"))
+		if lower := strings.ToLower(src); !strings.HasPrefix(lower, "http://") && !strings.HasPrefix(lower, "https://") {
+			return alt
+		}
+		return fmt.Sprintf("![%s](%s)", alt, markdownURL(src))
+	}
+	return inner
+}
+
+// escapeMarkdown escapes the characters the markdown converter would read as markup
+func escapeMarkdown(text string) string {
+	var escaped strings.Builder
+	for _, r := range text {
+		if strings.ContainsRune(markupCharacters, r) {
+			escaped.WriteByte('\\')
+		}
+		escaped.WriteRune(r)
+	}
+	return escaped.String()
+}
+
+// markdownURL percent-encodes the characters that would end a markdown link target
+func markdownURL(url string) string {
+	return strings.NewReplacer(" ", "%20", "(", "%28", ")", "%29", "\\", "%5C").Replace(strings.TrimSpace(url))
+}
+
+// textContent returns the text of a node and its descendants as written
+func textContent(node *nethtml.Node) string {
+	if node.Type == nethtml.TextNode {
+		return node.Data
+	}
+
+	var text strings.Builder
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		if child.Type == nethtml.ElementNode && child.DataAtom == atom.Br {
+			text.WriteString("\n")
+			continue
+		}
+		text.WriteString(textContent(child))
+	}
+	return text.String()
+}
+
+func attr(node *nethtml.Node, name string) string {
+	for _, a := range node.Attr {
+		if a.Key == name {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+func collapseSpace(text string) string {
+	fields := strings.Fields(text)
+	if len(fields) == 0 {
+		return ""
+	}
+
+	result := strings.Join(fields, " ")
+	if strings.TrimLeft(text, " \t\n\r") != text {
+		result = " " + result
+	}
+	if strings.TrimRight(text, " \t\n\r") != text {
+		result += " "
+	}
+	return result
+}
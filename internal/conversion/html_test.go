@@ -0,0 +1,37 @@
+package conversion
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestHTMLToDocsRequestsKeepsTextLiteral(t *testing.T) {
+	input := `<h2>snake_case</h2><p>a_b *c* [d](e) <code>f*g</code></p><p>---</p><pre>  if (x) {
+    y()
+  }
+</pre>`
+
+	requests, err := HTMLToDocsRequests(strings.NewReader(input), 1, MarkdownOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var inserted []string
+	code := 0
+	for _, r := range requests {
+		if r.InsertText != nil && r.InsertText.Text != "\n" {
+			inserted = append(inserted, r.InsertText.Text)
+		}
+		if r.UpdateTextStyle != nil && r.UpdateTextStyle.TextStyle.WeightedFontFamily != nil {
+			code++
+		}
+	}
+
+	want := []string{"snake_case\n", "a_b *c* [d](e) f*g\n", "---\n", "  if (x) {\n", "    y()\n", "  }\n"}
+	if strings.Join(inserted, "") != strings.Join(want, "") {
+		t.Errorf("inserted %q, want %q", inserted, want)
+	}
+	if code != 4 {
+		t.Errorf("got %d code font ranges, want 4 (inline code and three preformatted lines)", code)
+	}
+}
@@ -0,0 +1,151 @@
+package conversion
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// jiraEscaper protects characters that start wiki markup inside plain text.
+// Backslashes are left alone, since a doubled one is a line break.
+var jiraEscaper = strings.NewReplacer(
+	`{`, `\{`,
+	`}`, `\}`,
+	`[`, `\[`,
+	`]`, `\]`,
+	`|`, `\|`,
+	`*`, `\*`,
+	`_`, `\_`,
+	`-`, `\-`,
+	`+`, `\+`,
+	`^`, `\^`,
+	`~`, `\~`,
+	`!`, `\!`,
+)
+
+// jiraRenderer renders Jira (and Confluence legacy) wiki markup
+type jiraRenderer struct {
+	footnotes []string
+	inList    bool
+	out       strings.Builder
+}
+
+func (r *jiraRenderer) Begin(title string) {
+	r.out.WriteString(fmt.Sprintf("h1. %s\n\n", title))
+}
+
+func (r *jiraRenderer) block() {
+	if r.inList {
+		r.out.WriteString("\n")
+		r.inList = false
+	}
+}
+
+func (r *jiraRenderer) Heading(level int, id string, text string) {
+	r.block()
+	if id != "" {
+		r.out.WriteString(fmt.Sprintf("{anchor:%s}\n", id))
+	}
+	r.out.WriteString(fmt.Sprintf("h%d. %s\n\n", min(level+1, 6), text))
+}
+
+func (r *jiraRenderer) Paragraph(text string) {
+	r.block()
+	r.out.WriteString(text + "\n\n")
+}
+
+func (r *jiraRenderer) ListItem(level int, ordered bool, text string) {
+	marker := "*"
+	if ordered {
+		marker = "#"
+	}
+	r.out.WriteString(fmt.Sprintf("%s %s\n", strings.Repeat(marker, level+1), text))
+	r.inList = true
+}
+
+func (r *jiraRenderer) Table(table Table) {
+	r.block()
+
+	if table.Callout {
+		r.out.WriteString("{info}" + table.Rows[0][0] + "{info}\n\n")
+		return
+	}
+
+	for rowIdx, row := range table.Rows {
+		separator := "|"
+		if rowIdx == 0 {
+			separator = "||"
+		}
+		r.out.WriteString(separator + strings.Join(row, separator) + separator + "\n")
+	}
+	r.out.WriteString("\n")
+}
+
+func (r *jiraRenderer) CodeBlock(lines []string) {
+	r.block()
+	r.out.WriteString("{code}\n" + strings.Join(lines, "\n") + "\n{code}\n\n")
+}
+
+func (r *jiraRenderer) Break() {
+	r.block()
+	r.out.WriteString("----\n\n")
+}
+
+func (r *jiraRenderer) TableOfContents() {
+	r.block()
+	r.out.WriteString("{toc}\n\n")
+}
+
+func (r *jiraRenderer) Footnote(id string, number string, text string) {
+	r.footnotes = append(r.footnotes, fmt.Sprintf("^%s^ %s", number, text))
+}
+
+func (r *jiraRenderer) Inline(inline Inline) string {
+	switch inline.Kind {
+	case InlineImage:
+		return fmt.Sprintf("!%s|alt=%s!", filepath.Base(inline.Path), inline.Alt)
+	case InlineFootnote:
+		return fmt.Sprintf("^%s^", inline.FootnoteNumber)
+	case InlineEquation:
+		return "{{" + inline.Text + "}}"
+	case InlineRule:
+		return "----"
+	}
+
+	content := jiraEscaper.Replace(inline.Text)
+	if inline.Code {
+		content = wrapInline("{{", content, "}}")
+	}
+	if inline.Bold {
+		content = wrapInline("*", content, "*")
+	}
+	if inline.Italic {
+		content = wrapInline("_", content, "_")
+	}
+	if inline.Underline {
+		content = wrapInline("+", content, "+")
+	}
+	if inline.Strikethrough {
+		content = wrapInline("-", content, "-")
+	}
+	if inline.Baseline == "SUPERSCRIPT" {
+		content = wrapInline("^", content, "^")
+	} else if inline.Baseline == "SUBSCRIPT" {
+		content = wrapInline("~", content, "~")
+	}
+	if inline.Link != "" {
+		content = wrapInline("[", content, "|"+inline.Link+"]")
+	}
+
+	return content
+}
+
+func (r *jiraRenderer) End() string {
+	r.block()
+
+	if len(r.footnotes) > 0 {
+		r.out.WriteString("----\n" + strings.Join(r.footnotes, "\n") + "\n")
+	}
+
+	return r.out.String()
+}
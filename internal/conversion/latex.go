@@ -0,0 +1,197 @@
+package conversion
+
+import (
+	"fmt"
+	"strings"
+)
+
+var (
+	latexEscaper = strings.NewReplacer(
+		`\`, `\textbackslash{}`,
+		`&`, `\&`,
+		`%`, `\%`,
+		`$`, `\$`,
+		`#`, `\#`,
+		`_`, `\_`,
+		`{`, `\{`,
+		`}`, `\}`,
+		`~`, `\textasciitilde{}`,
+		`^`, `\textasciicircum{}`,
+	)
+	latexURLEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `#`, `\#`)
+)
+
+// latexSections are the sectioning commands for TITLE and HEADING_1 to HEADING_5
+var latexSections = []string{"part", "section", "subsection", "subsubsection", "paragraph", "subparagraph"}
+
+// latexRenderer renders a standalone LaTeX article
+type latexRenderer struct {
+	lists  []string
+	out    strings.Builder
+	title  string
+	titled bool
+}
+
+// Begin records the title; the preamble is written by End, once it is known
+// whether a TITLE paragraph replaces it
+func (r *latexRenderer) Begin(title string) {
+	r.title = latexEscaper.Replace(title)
+}
+
+// Heading maps TITLE to the document title, or to a part once content precedes
+// it, and HEADING_n to the matching sectioning command. Articles stop at
+// \subparagraph, so HEADING_6 becomes an unnumbered subparagraph in italics.
+func (r *latexRenderer) Heading(level int, id string, text string) {
+	r.closeLists(-1)
+	if level == 0 && r.out.Len() == 0 && !r.titled {
+		r.title, r.titled = text, true
+		return
+	}
+
+	if level < len(latexSections) {
+		r.out.WriteString(fmt.Sprintf("\\%s{%s}\n", latexSections[level], text))
+	} else {
+		r.out.WriteString(fmt.Sprintf("\\subparagraph*{\\emph{%s}}\n", text))
+	}
+	if id != "" {
+		r.out.WriteString(fmt.Sprintf("\\label{%s}\n", id))
+	}
+	r.out.WriteString("\n")
+}
+
+func (r *latexRenderer) Paragraph(text string) {
+	r.closeLists(-1)
+	r.out.WriteString(text + "\n\n")
+}
+
+func (r *latexRenderer) ListItem(level int, ordered bool, text string) {
+	env := "itemize"
+	if ordered {
+		env = "enumerate"
+	}
+
+	r.closeLists(level)
+	if len(r.lists) == level+1 && r.lists[level] != env {
+		r.closeLists(level - 1)
+	}
+	for len(r.lists) <= level {
+		r.out.WriteString(fmt.Sprintf("%s\\begin{%s}\n", strings.Repeat("  ", len(r.lists)), env))
+		r.lists = append(r.lists, env)
+	}
+
+	r.out.WriteString(fmt.Sprintf("%s\\item %s\n", strings.Repeat("  ", level+1), text))
+}
+
+// closeLists closes open list environments deeper than level (-1 closes all of them)
+func (r *latexRenderer) closeLists(level int) {
+	for len(r.lists) > level+1 {
+		env := r.lists[len(r.lists)-1]
+		r.lists = r.lists[:len(r.lists)-1]
+		r.out.WriteString(fmt.Sprintf("%s\\end{%s}\n", strings.Repeat("  ", len(r.lists)), env))
+		if len(r.lists) == 0 {
+			r.out.WriteString("\n")
+		}
+	}
+}
+
+func (r *latexRenderer) Table(table Table) {
+	r.closeLists(-1)
+
+	columns := 0
+	for _, row := range table.Rows {
+		columns = max(columns, len(row))
+	}
+	if columns == 0 {
+		return
+	}
+
+	r.out.WriteString(fmt.Sprintf("\\begin{tabular}{|%s}\n\\hline\n", strings.Repeat("l|", columns)))
+	for rowIdx, row := range table.Rows {
+		cells := make([]string, columns)
+		for i, cell := range row {
+			if rowIdx == 0 && cell != "" {
+				cell = "\\textbf{" + cell + "}"
+			}
+			cells[i] = cell
+		}
+		r.out.WriteString(strings.Join(cells, " & ") + " \\\\\n\\hline\n")
+	}
+	r.out.WriteString("\\end{tabular}\n\n")
+}
+
+func (r *latexRenderer) CodeBlock(lines []string) {
+	r.closeLists(-1)
+	r.out.WriteString("\\begin{verbatim}\n" + strings.Join(lines, "\n") + "\n\\end{verbatim}\n\n")
+}
+
+func (r *latexRenderer) Break() {
+	r.closeLists(-1)
+	r.out.WriteString("\\noindent\\rule{\\linewidth}{0.4pt}\n\n")
+}
+
+func (r *latexRenderer) TableOfContents() {
+	r.closeLists(-1)
+	r.out.WriteString("\\tableofcontents\n\n")
+}
+
+// Footnote is a no-op: LaTeX footnotes are written inline
+func (r *latexRenderer) Footnote(id string, number string, text string) {}
+
+func (r *latexRenderer) Inline(inline Inline) string {
+	switch inline.Kind {
+	case InlineImage:
+		return fmt.Sprintf("\\includegraphics[width=\\linewidth]{%s}", inline.Path)
+	case InlineFootnote:
+		return fmt.Sprintf("\\footnote{%s}", inline.Text)
+	case InlineEquation:
+		return "$\\ldots$"
+	case InlineRule:
+		return "\\rule{\\linewidth}{0.4pt}"
+	}
+
+	content := latexEscaper.Replace(inline.Text)
+	if inline.Code {
+		content = wrapInline("\\texttt{", content, "}")
+	}
+	if inline.Bold {
+		content = wrapInline("\\textbf{", content, "}")
+	}
+	if inline.Italic {
+		content = wrapInline("\\emph{", content, "}")
+	}
+	if inline.Underline {
+		content = wrapInline("\\uline{", content, "}")
+	}
+	if inline.Strikethrough {
+		content = wrapInline("\\sout{", content, "}")
+	}
+	if inline.Baseline == "SUPERSCRIPT" {
+		content = wrapInline("\\textsuperscript{", content, "}")
+	} else if inline.Baseline == "SUBSCRIPT" {
+		content = wrapInline("\\textsubscript{", content, "}")
+	}
+	if inline.Link != "" {
+		if strings.HasPrefix(inline.Link, "#") {
+			content = wrapInline("\\hyperref["+strings.TrimPrefix(inline.Link, "#")+"]{", content, "}")
+		} else {
+			content = wrapInline("\\href{"+latexURLEscaper.Replace(inline.Link)+"}{", content, "}")
+		}
+	}
+
+	return content
+}
+
+func (r *latexRenderer) End() string {
+	r.closeLists(-1)
+
+	var preamble strings.Builder
+	preamble.WriteString("\\documentclass{article}\n")
+	preamble.WriteString("\\usepackage[utf8]{inputenc}\n")
+	preamble.WriteString("\\usepackage{graphicx}\n")
+	preamble.WriteString("\\usepackage[normalem]{ulem}\n")
+	preamble.WriteString("\\usepackage{hyperref}\n\n")
+	preamble.WriteString(fmt.Sprintf("\\title{%s}\n\\date{}\n\n", r.title))
+	preamble.WriteString("\\begin{document}\n\\maketitle\n\n")
+
+	return preamble.String() + r.out.String() + "\\end{document}\n"
+}
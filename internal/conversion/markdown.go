@@ -9,47 +9,26 @@ import (
 	"google.golang.org/api/docs/v1"
 )
 
+const (
+	// breakMarker renders section breaks and horizontal rules, and creates a section break on import
+	breakMarker = "---"
+	// equationPlaceholder stands in for equations, whose content is not exposed by the API
+	equationPlaceholder = "$…$"
+	// markupCharacters can be escaped with a backslash to be inserted literally;
+	// the hyphen stays last since the list also builds a regexp character class
+	markupCharacters = "\\`*_{}[]()#~!+<-"
+	// tableOfContentsMarker renders a table of contents; the API cannot create one on import
+	tableOfContentsMarker = "[TOC]"
+)
+
 // DocsToMarkdown converts a Google Doc to markdown format
 func DocsToMarkdown(doc *docs.Document) string {
-	var md strings.Builder
-
-	md.WriteString(fmt.Sprintf("# %s\n\n", doc.Title))
-
-	for _, element := range doc.Body.Content {
-		if element.Paragraph != nil {
-			paragraph := element.Paragraph
-
-			if paragraph.ParagraphStyle != nil && paragraph.ParagraphStyle.NamedStyleType != "" {
-				styleType := paragraph.ParagraphStyle.NamedStyleType
-				text := GetParagraphText(paragraph)
-
-				// Map Google Docs styles to markdown heading levels
-				// TITLE → #, HEADING_1 → ##, HEADING_2 → ###, etc.
-				switch styleType {
-				case "TITLE":
-					md.WriteString(fmt.Sprintf("# %s\n\n", text))
-				case "HEADING_1":
-					md.WriteString(fmt.Sprintf("## %s\n\n", text))
-				case "HEADING_2":
-					md.WriteString(fmt.Sprintf("### %s\n\n", text))
-				case "HEADING_3":
-					md.WriteString(fmt.Sprintf("#### %s\n\n", text))
-				case "HEADING_4":
-					md.WriteString(fmt.Sprintf("##### %s\n\n", text))
-				case "HEADING_5":
-					md.WriteString(fmt.Sprintf("###### %s\n\n", text))
-				default:
-					md.WriteString(formatParagraphAsMarkdown(paragraph))
-				}
-			} else {
-				md.WriteString(formatParagraphAsMarkdown(paragraph))
-			}
-		} else if element.Table != nil {
-			md.WriteString(tableToMarkdown(element.Table))
-		}
-	}
+	return DocsToMarkdownWithOptions(doc, RenderOptions{})
+}
 
-	return md.String()
+// DocsToMarkdownWithOptions converts a Google Doc to markdown format using the given options
+func DocsToMarkdownWithOptions(doc *docs.Document, opts RenderOptions) string {
+	return Render(doc, &markdownRenderer{}, opts)
 }
 
 // GetParagraphText extracts text from a paragraph
@@ -63,80 +42,241 @@ func GetParagraphText(paragraph *docs.Paragraph) string {
 	return strings.TrimSpace(text.String())
 }
 
-func formatParagraphAsMarkdown(paragraph *docs.Paragraph) string {
-	var text strings.Builder
+// IsOrderedList reports whether a bullet's nesting level uses numbered glyphs
+func IsOrderedList(doc *docs.Document, bullet *docs.Bullet) bool {
+	list, ok := doc.Lists[bullet.ListId]
+	if !ok || list.ListProperties == nil {
+		return false
+	}
 
-	for _, element := range paragraph.Elements {
-		if element.TextRun != nil {
-			content := element.TextRun.Content
-			style := element.TextRun.TextStyle
+	levels := list.ListProperties.NestingLevels
+	if int(bullet.NestingLevel) >= len(levels) {
+		return false
+	}
 
-			if style != nil {
-				if style.Bold {
-					content = fmt.Sprintf("**%s**", strings.TrimSpace(content))
-				}
-				if style.Italic {
-					content = fmt.Sprintf("*%s*", strings.TrimSpace(content))
-				}
-				if style.Link != nil {
-					url := style.Link.Url
-					content = fmt.Sprintf("[%s](%s)", strings.TrimSpace(content), url)
-				}
-			}
+	glyphType := levels[bullet.NestingLevel].GlyphType
+	return glyphType != "" && glyphType != "GLYPH_TYPE_UNSPECIFIED" && glyphType != "NONE"
+}
 
-			text.WriteString(content)
-		}
+// markdownEscaper protects characters that start inline markup in plain text
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	"`", "\\`",
+	`*`, `\*`,
+	`_`, `\_`,
+	`{`, `\{`,
+	`}`, `\}`,
+	`[`, `\[`,
+	`]`, `\]`,
+	`~`, `\~`,
+	`<`, `\<`,
+)
+
+// markdownRenderer renders markdown, with suggestions and comments as CriticMarkup
+type markdownRenderer struct {
+	footnotes []string
+	inList    bool
+	out       strings.Builder
+}
+
+func (r *markdownRenderer) Begin(title string) {
+	r.out.WriteString(fmt.Sprintf("# %s\n\n", title))
+}
+
+// block starts a new block, ending any open list with a blank line
+func (r *markdownRenderer) block() {
+	if r.inList {
+		r.out.WriteString("\n")
+		r.inList = false
+	}
+}
+
+func (r *markdownRenderer) Heading(level int, id string, text string) {
+	r.block()
+
+	// Map Google Docs styles to markdown heading levels
+	// TITLE → #, HEADING_1 → ##, HEADING_2 → ###, etc.
+	r.out.WriteString(fmt.Sprintf("%s %s\n\n", strings.Repeat("#", min(level+1, 6)), text))
+}
+
+func (r *markdownRenderer) Paragraph(text string) {
+	r.block()
+
+	// Keep paragraphs from reading as headings, rules or list items; a
+	// horizontal rule on its own is meant as one
+	if text != breakMarker && (strings.HasPrefix(text, "#") || strings.HasPrefix(text, "-") || strings.HasPrefix(text, "+")) {
+		text = "\\" + text
 	}
+	r.out.WriteString(text + "\n\n")
+}
 
-	result := text.String()
-	if strings.TrimSpace(result) != "" {
-		return result + "\n\n"
+func (r *markdownRenderer) ListItem(level int, ordered bool, text string) {
+	marker := "-"
+	if ordered {
+		marker = "1."
 	}
-	return ""
+	r.out.WriteString(fmt.Sprintf("%s%s %s\n", strings.Repeat("    ", level), marker, text))
+	r.inList = true
 }
 
-func tableToMarkdown(table *docs.Table) string {
-	var md strings.Builder
+func (r *markdownRenderer) Table(table Table) {
+	r.block()
 
-	for rowIdx, row := range table.TableRows {
-		md.WriteString("|")
-		for _, cell := range row.TableCells {
-			cellText := getTableCellText(cell)
-			md.WriteString(fmt.Sprintf(" %s |", cellText))
+	for rowIdx, row := range table.Rows {
+		r.out.WriteString("|")
+		for _, cell := range row {
+			r.out.WriteString(fmt.Sprintf(" %s |", strings.ReplaceAll(cell, "|", "\\|")))
 		}
-		md.WriteString("\n")
+		r.out.WriteString("\n")
 
 		if rowIdx == 0 {
-			md.WriteString("|")
-			for range row.TableCells {
-				md.WriteString(" --- |")
+			r.out.WriteString("|")
+			for range row {
+				r.out.WriteString(" --- |")
 			}
-			md.WriteString("\n")
+			r.out.WriteString("\n")
 		}
 	}
 
-	md.WriteString("\n")
-	return md.String()
+	r.out.WriteString("\n")
 }
 
-func getTableCellText(cell *docs.TableCell) string {
-	var text strings.Builder
-	for _, element := range cell.Content {
-		if element.Paragraph != nil {
-			text.WriteString(GetParagraphText(element.Paragraph))
+func (r *markdownRenderer) CodeBlock(lines []string) {
+	r.block()
+	r.out.WriteString("```\n" + strings.Join(lines, "\n") + "\n```\n\n")
+}
+
+func (r *markdownRenderer) Break() {
+	r.block()
+	r.out.WriteString(breakMarker + "\n\n")
+}
+
+func (r *markdownRenderer) TableOfContents() {
+	r.block()
+	r.out.WriteString(tableOfContentsMarker + "\n\n")
+}
+
+func (r *markdownRenderer) Footnote(id string, number string, text string) {
+	r.footnotes = append(r.footnotes, fmt.Sprintf("[^%s]: %s", number, text))
+}
+
+func (r *markdownRenderer) Inline(inline Inline) string {
+	switch inline.Kind {
+	case InlineImage:
+		if inline.Title != "" {
+			return fmt.Sprintf("![%s](%s %q)", inline.Alt, inline.Path, inline.Title)
 		}
+		return fmt.Sprintf("![%s](%s)", inline.Alt, inline.Path)
+	case InlineFootnote:
+		return fmt.Sprintf("[^%s]", inline.FootnoteNumber)
+	case InlineEquation:
+		return equationPlaceholder
+	case InlineRule:
+		return breakMarker
 	}
-	return strings.TrimSpace(text.String())
+
+	content := markdownEscaper.Replace(inline.Text)
+	if inline.Code {
+		// Code spans are literal
+		content = wrapInline("`", inline.Text, "`")
+	}
+	if inline.Person {
+		// The @ marks the link as a person chip for import
+		content = "@" + content
+	}
+	if inline.Bold {
+		content = wrapInline("**", content, "**")
+	}
+	if inline.Italic {
+		content = wrapInline("*", content, "*")
+	}
+	if inline.Strikethrough {
+		content = wrapInline("~~", content, "~~")
+	}
+	if inline.Link != "" {
+		content = wrapInline("[", content, "]("+inline.Link+")")
+	}
+
+	// Suggestions are rendered as CriticMarkup
+	if inline.Deleted {
+		content = wrapInline("{--", content, "--}")
+	}
+	if inline.Inserted {
+		content = wrapInline("{++", content, "++}")
+	}
+
+	return content
+}
+
+func (r *markdownRenderer) End() string {
+	r.block()
+
+	if len(r.footnotes) > 0 {
+		r.out.WriteString(strings.Join(r.footnotes, "\n") + "\n\n")
+	}
+
+	return r.out.String()
+}
+
+// Annotate highlights commented text with {== ==}, followed by a {>> <<}
+// annotation after the comment's last piece
+func (r *markdownRenderer) Annotate(comment Comment, text string, last bool) string {
+	if last {
+		return wrapInline("{==", text, "==}") + fmt.Sprintf("{>>%s<<}", commentText(comment))
+	}
+	return wrapInline("{==", text, "==}")
+}
+
+// MarkdownOptions controls how markdown is converted to Docs API requests
+type MarkdownOptions struct {
+	// Presets are applied to paragraphs ending in {.name} and to [text]{.name} spans
+	Presets map[string]*Preset
 }
 
 // MarkdownToDocsRequests converts markdown to Docs API requests
 func MarkdownToDocsRequests(markdown string, startIndex int64) []*docs.Request {
+	return MarkdownToDocsRequestsWithOptions(markdown, startIndex, MarkdownOptions{})
+}
+
+// MarkdownToDocsRequestsWithOptions converts markdown to Docs API requests with the given options
+func MarkdownToDocsRequestsWithOptions(markdown string, startIndex int64, opts MarkdownOptions) []*docs.Request {
+	requests, _ := markdownRequests(markdown, startIndex, opts)
+	return requests
+}
+
+// markdownRequests converts markdown to Docs API requests, also returning the
+// index right after the inserted content
+func markdownRequests(markdown string, startIndex int64, opts MarkdownOptions) ([]*docs.Request, int64) {
 	var requests []*docs.Request
 	lines := strings.Split(markdown, "\n")
 	currentIndex := startIndex
 
 	for _, line := range lines {
 		line = strings.TrimSpace(line)
+		if line == tableOfContentsMarker {
+			// Tables of contents cannot be created through the API
+			continue
+		}
+
+		if line == breakMarker {
+			requests = append(requests, &docs.Request{
+				InsertSectionBreak: &docs.InsertSectionBreakRequest{
+					Location:    &docs.Location{Index: currentIndex},
+					SectionType: "CONTINUOUS",
+				},
+			})
+			// A newline is inserted before the section break itself
+			currentIndex += 2
+			continue
+		}
+
+		var preset *Preset
+		if match := blockPresetRegex.FindStringSubmatchIndex(line); match != nil {
+			if preset = opts.Presets[line[match[2]:match[3]]]; preset != nil {
+				line = strings.TrimSpace(line[:match[0]])
+			}
+		}
+
 		if line == "" {
 			requests = append(requests, &docs.Request{
 				InsertText: &docs.InsertTextRequest{
@@ -144,6 +284,8 @@ func MarkdownToDocsRequests(markdown string, startIndex int64) []*docs.Request {
 					Text:     "\n",
 				},
 			})
+			requests = append(requests, normalTextRequest(currentIndex, currentIndex+1))
+			requests = append(requests, presetRequests(preset, currentIndex, currentIndex+1)...)
 			currentIndex++
 			continue
 		}
@@ -157,7 +299,7 @@ func MarkdownToDocsRequests(markdown string, startIndex int64) []*docs.Request {
 					break
 				}
 			}
-			text := strings.TrimSpace(line[level:])
+			text, formatRequests := parseInlineFormatting(strings.TrimSpace(line[level:]), currentIndex, opts.Presets)
 
 			requests = append(requests, &docs.Request{
 				InsertText: &docs.InsertTextRequest{
@@ -188,10 +330,12 @@ func MarkdownToDocsRequests(markdown string, startIndex int64) []*docs.Request {
 					Fields: "namedStyleType",
 				},
 			})
+			requests = append(requests, presetRequests(preset, currentIndex, currentIndex+textLen+1)...)
+			requests = append(requests, formatRequests...)
 
 			currentIndex += textLen + 1
 		} else {
-			processedText, formatRequests := parseInlineFormatting(line, currentIndex)
+			processedText, formatRequests := parseInlineFormatting(line, currentIndex, opts.Presets)
 
 			requests = append(requests, &docs.Request{
 				InsertText: &docs.InsertTextRequest{
@@ -200,100 +344,308 @@ func MarkdownToDocsRequests(markdown string, startIndex int64) []*docs.Request {
 				},
 			})
 
+			textLen := int64(utf8.RuneCountInString(processedText))
+			requests = append(requests, normalTextRequest(currentIndex, currentIndex+textLen+1))
+			requests = append(requests, presetRequests(preset, currentIndex, currentIndex+textLen+1)...)
 			requests = append(requests, formatRequests...)
 
-			textLen := int64(utf8.RuneCountInString(processedText))
 			currentIndex += textLen + 1
 		}
 	}
 
-	return requests
+	return requests, currentIndex
 }
 
-func parseInlineFormatting(text string, startIndex int64) (string, []*docs.Request) {
-	var requests []*docs.Request
+// normalTextRequest resets inserted paragraphs to normal text, since inserted
+// text otherwise takes the style of the paragraph it was inserted into
+func normalTextRequest(startIndex, endIndex int64) *docs.Request {
+	return &docs.Request{
+		UpdateParagraphStyle: &docs.UpdateParagraphStyleRequest{
+			Fields: "namedStyleType",
+			ParagraphStyle: &docs.ParagraphStyle{
+				NamedStyleType: "NORMAL_TEXT",
+			},
+			Range: &docs.Range{
+				EndIndex:   endIndex,
+				StartIndex: startIndex,
+			},
+		},
+	}
+}
 
-	boldRegex := regexp.MustCompile(`\*\*([^*]+)\*\*|__([^_]+)__`)
-	matches := boldRegex.FindAllStringSubmatchIndex(text, -1)
+// presetRequests applies a paragraph's preset, if any, to the paragraph
+func presetRequests(preset *Preset, startIndex, endIndex int64) []*docs.Request {
+	if preset == nil {
+		return nil
+	}
+	return preset.Requests(&docs.Range{EndIndex: endIndex, StartIndex: startIndex})
+}
 
-	offset := int64(0)
-	for _, match := range matches {
-		matchStart := match[0]
-		matchEnd := match[1]
-
-		var content string
-		if match[2] != -1 && match[3] != -1 {
-			content = text[match[2]:match[3]]
-		} else if match[4] != -1 && match[5] != -1 {
-			content = text[match[4]:match[5]]
+var (
+	blockPresetRegex  = regexp.MustCompile(`\s*\{\.([A-Za-z][\w-]*)\}$`)
+	boldRegex         = regexp.MustCompile(`\*\*([^*]+)\*\*|__([^_]+)__`)
+	codeRegex         = regexp.MustCompile("`([^`]+)`")
+	escapeRegex       = regexp.MustCompile(`\\([` + regexp.QuoteMeta(markupCharacters) + `])`)
+	imageRegex        = regexp.MustCompile(`!\[([^\]]*)\]\(([^)\s]+)\)`)
+	inlinePresetRegex = regexp.MustCompile(`\[([^\]]+)\]\{\.([A-Za-z][\w-]*)\}`)
+	italicRegex       = regexp.MustCompile(`\*([^*]+)\*|_([^_]+)_`)
+	linkRegex         = regexp.MustCompile(`\[([^\]]+)\]\(([^)\s]+)\)`)
+	strikeRegex       = regexp.MustCompile(`~~([^~]+)~~`)
+)
+
+const (
+	// escapeBase offsets the private use characters that stand in for escaped
+	// markup characters until inline parsing is done
+	escapeBase = 0xE000
+	// objectPlaceholder occupies the single index of a person chip or inline
+	// image until it is replaced
+	objectPlaceholder = "\uFFFC"
+)
+
+// inlineSpan is a formatted range of a line, in runes relative to the line start
+type inlineSpan struct {
+	end    int64
+	fields string
+	image  string
+	person *docs.PersonProperties
+	start  int64
+	style  *docs.TextStyle
+}
+
+// inlineMatch records how a markup match was rewritten, in runes
+type inlineMatch struct {
+	contentEnd   int64
+	contentStart int64
+	end          int64
+	replacement  int64
+	start        int64
+}
+
+func parseInlineFormatting(text string, startIndex int64, presets map[string]*Preset) (string, []*docs.Request) {
+	var spans []inlineSpan
+
+	// Escaped characters are hidden from the passes below, keeping their length
+	text, spans = stripInline(text, escapeRegex, spans, func(groups []string) (string, inlineSpan) {
+		return string(rune(escapeBase) + rune(groups[0][0])), inlineSpan{}
+	})
+
+	text, spans = stripInline(text, codeRegex, spans, func(groups []string) (string, inlineSpan) {
+		return protectMarkup(groups[0]), inlineSpan{fields: "weightedFontFamily", style: codeTextStyle()}
+	})
+
+	text, spans = stripInline(text, inlinePresetRegex, spans, func(groups []string) (string, inlineSpan) {
+		label, name := groups[0], groups[1]
+		preset := presets[name]
+		if preset == nil {
+			// Unknown presets are left as written
+			return "[" + label + "]{." + name + "}", inlineSpan{}
+		}
+		if len(preset.TextFields) == 0 {
+			return label, inlineSpan{}
+		}
+		return label, inlineSpan{
+			fields: strings.Join(preset.TextFields, ","),
+			style:  preset.TextStyle,
+		}
+	})
+
+	text, spans = stripInline(text, imageRegex, spans, func(groups []string) (string, inlineSpan) {
+		alt, url := groups[0], restoreEscapes(groups[1])
+		// Only images Docs can fetch are inserted; others keep their alt text
+		if lower := strings.ToLower(url); !strings.HasPrefix(lower, "http://") && !strings.HasPrefix(lower, "https://") {
+			return alt, inlineSpan{}
+		}
+		return objectPlaceholder, inlineSpan{image: url}
+	})
+
+	text, spans = stripInline(text, linkRegex, spans, func(groups []string) (string, inlineSpan) {
+		label, url := groups[0], restoreEscapes(groups[1])
+		// Only [@Name](mailto:…) and links labelled with their own address are chips
+		if email, ok := strings.CutPrefix(url, "mailto:"); ok && (strings.HasPrefix(label, "@") || label == email) {
+			return objectPlaceholder, inlineSpan{
+				person: &docs.PersonProperties{Email: email},
+			}
 		}
+		return label, inlineSpan{
+			fields: "link",
+			style:  &docs.TextStyle{Link: &docs.Link{Url: url}},
+		}
+	})
+
+	text, spans = stripInline(text, boldRegex, spans, func(groups []string) (string, inlineSpan) {
+		return groups[0] + groups[1], inlineSpan{fields: "bold", style: &docs.TextStyle{Bold: true}}
+	})
 
-		contentRuneCount := int64(utf8.RuneCountInString(content))
-		matchRuneCount := int64(utf8.RuneCountInString(text[matchStart:matchEnd]))
-		markerLength := matchRuneCount - contentRuneCount
+	text, spans = stripInline(text, italicRegex, spans, func(groups []string) (string, inlineSpan) {
+		return groups[0] + groups[1], inlineSpan{fields: "italic", style: &docs.TextStyle{Italic: true}}
+	})
 
-		matchStartRunes := int64(utf8.RuneCountInString(text[:matchStart]))
-		adjustedStart := matchStartRunes - offset
-		adjustedEnd := adjustedStart + contentRuneCount
+	text, spans = stripInline(text, strikeRegex, spans, func(groups []string) (string, inlineSpan) {
+		return groups[0], inlineSpan{fields: "strikethrough", style: &docs.TextStyle{Strikethrough: true}}
+	})
+
+	text = restoreEscapes(text)
+
+	var requests []*docs.Request
+	var objectRequests []*docs.Request
+
+	for _, span := range spans {
+		spanRange := &docs.Range{
+			EndIndex:   startIndex + span.end,
+			StartIndex: startIndex + span.start,
+		}
+
+		// Person chips and inline images take one index, so swapping the
+		// placeholder keeps later indices valid
+		if span.person != nil {
+			objectRequests = append(objectRequests,
+				&docs.Request{DeleteContentRange: &docs.DeleteContentRangeRequest{Range: spanRange}},
+				&docs.Request{InsertPerson: &docs.InsertPersonRequest{
+					Location:         &docs.Location{Index: spanRange.StartIndex},
+					PersonProperties: span.person,
+				}},
+			)
+			continue
+		}
+		if span.image != "" {
+			objectRequests = append(objectRequests,
+				&docs.Request{DeleteContentRange: &docs.DeleteContentRangeRequest{Range: spanRange}},
+				&docs.Request{InsertInlineImage: &docs.InsertInlineImageRequest{
+					Location: &docs.Location{Index: spanRange.StartIndex},
+					Uri:      span.image,
+				}},
+			)
+			continue
+		}
+		if span.style == nil {
+			continue
+		}
 
 		requests = append(requests, &docs.Request{
 			UpdateTextStyle: &docs.UpdateTextStyleRequest{
-				Range: &docs.Range{
-					StartIndex: startIndex + adjustedStart,
-					EndIndex:   startIndex + adjustedEnd,
-				},
-				TextStyle: &docs.TextStyle{
-					Bold: true,
-				},
-				Fields: "bold",
+				Fields:    span.fields,
+				Range:     spanRange,
+				TextStyle: span.style,
 			},
 		})
-
-		offset += markerLength
 	}
 
-	plainText := boldRegex.ReplaceAllString(text, "$1$2")
+	return text, append(requests, objectRequests...)
+}
+
+// protectMarkup hides markup characters from later inline passes, as escaping does
+func protectMarkup(text string) string {
+	return strings.Map(func(r rune) rune {
+		if r < utf8.RuneSelf && strings.ContainsRune(markupCharacters, r) {
+			return escapeBase + r
+		}
+		return r
+	}, text)
+}
+
+// restoreEscapes turns the stand-ins left by escaping back into the characters
+func restoreEscapes(text string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= escapeBase && r < escapeBase+utf8.RuneSelf {
+			return r - escapeBase
+		}
+		return r
+	}, text)
+}
 
-	italicRegex := regexp.MustCompile(`\*([^*]+)\*|_([^_]+)_`)
-	italicMatches := italicRegex.FindAllStringSubmatchIndex(plainText, -1)
+// codeTextStyle is the monospace font used for inline code and code blocks
+func codeTextStyle() *docs.TextStyle {
+	return &docs.TextStyle{WeightedFontFamily: &docs.WeightedFontFamily{FontFamily: "Courier New"}}
+}
+
+// stripInline replaces every match of re in text with the replacement returned by
+// build, which receives the match's capture groups (empty when unmatched). Spans
+// found by earlier passes are shifted to stay aligned with the rewritten text, and
+// a new span covering each replacement is appended.
+func stripInline(text string, re *regexp.Regexp, spans []inlineSpan, build func(groups []string) (string, inlineSpan)) (string, []inlineSpan) {
+	matches := re.FindAllStringSubmatchIndex(text, -1)
+	if len(matches) == 0 {
+		return text, spans
+	}
 
-	offset = int64(0)
-	for _, match := range italicMatches {
-		matchStart := match[0]
-		matchEnd := match[1]
+	var out strings.Builder
+	var rewritten []inlineMatch
+	var added []inlineSpan
+	last := 0
 
-		var content string
-		if match[2] != -1 && match[3] != -1 {
-			content = plainText[match[2]:match[3]]
-		} else if match[4] != -1 && match[5] != -1 {
-			content = plainText[match[4]:match[5]]
+	for _, match := range matches {
+		groups := make([]string, 0, len(match)/2-1)
+		contentStart, contentEnd := -1, -1
+		for g := 2; g < len(match); g += 2 {
+			if match[g] == -1 {
+				groups = append(groups, "")
+				continue
+			}
+			groups = append(groups, text[match[g]:match[g+1]])
+			if contentStart == -1 {
+				contentStart, contentEnd = match[g], match[g+1]
+			}
 		}
 
-		contentRuneCount := int64(utf8.RuneCountInString(content))
-		matchRuneCount := int64(utf8.RuneCountInString(plainText[matchStart:matchEnd]))
-		markerLength := matchRuneCount - contentRuneCount
+		replacement, span := build(groups)
 
-		matchStartRunes := int64(utf8.RuneCountInString(plainText[:matchStart]))
-		adjustedStart := matchStartRunes - offset
-		adjustedEnd := adjustedStart + contentRuneCount
+		out.WriteString(text[last:match[0]])
+		newStart := int64(utf8.RuneCountInString(out.String()))
+		out.WriteString(replacement)
+		replacementLen := int64(utf8.RuneCountInString(replacement))
+		last = match[1]
 
-		requests = append(requests, &docs.Request{
-			UpdateTextStyle: &docs.UpdateTextStyleRequest{
-				Range: &docs.Range{
-					StartIndex: startIndex + adjustedStart,
-					EndIndex:   startIndex + adjustedEnd,
-				},
-				TextStyle: &docs.TextStyle{
-					Italic: true,
-				},
-				Fields: "italic",
-			},
-		})
+		span.start = newStart
+		span.end = newStart + replacementLen
+		added = append(added, span)
+
+		m := inlineMatch{
+			end:         int64(utf8.RuneCountInString(text[:match[1]])),
+			replacement: replacementLen,
+			start:       int64(utf8.RuneCountInString(text[:match[0]])),
+		}
+		if contentStart != -1 && text[contentStart:contentEnd] == replacement {
+			m.contentStart = int64(utf8.RuneCountInString(text[:contentStart]))
+			m.contentEnd = int64(utf8.RuneCountInString(text[:contentEnd]))
+		} else {
+			m.contentStart, m.contentEnd = -1, -1
+		}
+		rewritten = append(rewritten, m)
+	}
+	out.WriteString(text[last:])
 
-		offset += markerLength
+	for i := range spans {
+		spans[i].start = remapOffset(spans[i].start, rewritten)
+		spans[i].end = remapOffset(spans[i].end, rewritten)
 	}
 
-	plainText = italicRegex.ReplaceAllString(plainText, "$1$2")
+	return out.String(), append(spans, added...)
+}
+
+// remapOffset converts a rune offset in the original text to the rewritten text
+func remapOffset(offset int64, matches []inlineMatch) int64 {
+	shift := int64(0)
+
+	for _, m := range matches {
+		if offset <= m.start {
+			break
+		}
+
+		if offset >= m.end {
+			shift += (m.end - m.start) - m.replacement
+			continue
+		}
+
+		// Offset falls inside the markup: keep it aligned with the content when the
+		// content was preserved, otherwise snap it to the replacement boundary
+		if m.contentStart != -1 && offset >= m.contentStart && offset <= m.contentEnd {
+			return offset - shift - (m.contentStart - m.start)
+		}
+		if offset < m.contentStart {
+			return m.start - shift
+		}
+		return m.start - shift + m.replacement
+	}
 
-	return plainText, requests
+	return offset - shift
 }
@@ -0,0 +1,148 @@
+package conversion
+
+import (
+	"fmt"
+	"strings"
+)
+
+// orgRenderer renders Emacs Org-mode markup
+type orgRenderer struct {
+	footnotes []string
+	inList    bool
+	out       strings.Builder
+	title     string
+	titled    bool
+}
+
+// Begin records the title; the header is written by End, once it is known
+// whether a TITLE paragraph replaces it
+func (r *orgRenderer) Begin(title string) {
+	r.title = title
+}
+
+func (r *orgRenderer) block() {
+	if r.inList {
+		r.out.WriteString("\n")
+		r.inList = false
+	}
+}
+
+// Heading maps TITLE to the document title, or to a top-level headline once
+// content precedes it. HEADING_n is nested one level below, so it stays
+// distinct from TITLE.
+func (r *orgRenderer) Heading(level int, id string, text string) {
+	r.block()
+	if level == 0 && r.out.Len() == 0 && !r.titled {
+		r.title, r.titled = text, true
+		return
+	}
+
+	r.out.WriteString(fmt.Sprintf("%s %s\n", strings.Repeat("*", level+1), text))
+	if id != "" {
+		r.out.WriteString(fmt.Sprintf(":PROPERTIES:\n:CUSTOM_ID: %s\n:END:\n", id))
+	}
+	r.out.WriteString("\n")
+}
+
+func (r *orgRenderer) Paragraph(text string) {
+	r.block()
+	r.out.WriteString(text + "\n\n")
+}
+
+func (r *orgRenderer) ListItem(level int, ordered bool, text string) {
+	marker := "-"
+	if ordered {
+		marker = "1."
+	}
+	r.out.WriteString(fmt.Sprintf("%s%s %s\n", strings.Repeat("  ", level), marker, text))
+	r.inList = true
+}
+
+func (r *orgRenderer) Table(table Table) {
+	r.block()
+	for rowIdx, row := range table.Rows {
+		cells := make([]string, len(row))
+		for i, cell := range row {
+			cells[i] = strings.ReplaceAll(cell, "|", "\\vert{}")
+		}
+		r.out.WriteString("| " + strings.Join(cells, " | ") + " |\n")
+
+		if rowIdx == 0 && len(table.Rows) > 1 {
+			separators := make([]string, len(row))
+			for i := range separators {
+				separators[i] = "---"
+			}
+			r.out.WriteString("|" + strings.Join(separators, "+") + "|\n")
+		}
+	}
+	r.out.WriteString("\n")
+}
+
+func (r *orgRenderer) CodeBlock(lines []string) {
+	r.block()
+	r.out.WriteString("#+BEGIN_SRC\n" + strings.Join(lines, "\n") + "\n#+END_SRC\n\n")
+}
+
+func (r *orgRenderer) Break() {
+	r.block()
+	r.out.WriteString("-----\n\n")
+}
+
+func (r *orgRenderer) TableOfContents() {
+	r.block()
+	r.out.WriteString("#+TOC: headlines 3\n\n")
+}
+
+func (r *orgRenderer) Footnote(id string, number string, text string) {
+	r.footnotes = append(r.footnotes, fmt.Sprintf("[fn:%s] %s", number, text))
+}
+
+func (r *orgRenderer) Inline(inline Inline) string {
+	switch inline.Kind {
+	case InlineImage:
+		return fmt.Sprintf("[[file:%s]]", inline.Path)
+	case InlineFootnote:
+		return fmt.Sprintf("[fn:%s]", inline.FootnoteNumber)
+	case InlineEquation:
+		return "=" + inline.Text + "="
+	case InlineRule:
+		return "-----"
+	}
+
+	content := inline.Text
+	if inline.Code {
+		content = wrapInline("~", content, "~")
+	}
+	if inline.Bold {
+		content = wrapInline("*", content, "*")
+	}
+	if inline.Italic {
+		content = wrapInline("/", content, "/")
+	}
+	if inline.Underline {
+		content = wrapInline("_", content, "_")
+	}
+	if inline.Strikethrough {
+		content = wrapInline("+", content, "+")
+	}
+	if inline.Baseline == "SUPERSCRIPT" {
+		content = wrapInline("^{", content, "}")
+	} else if inline.Baseline == "SUBSCRIPT" {
+		content = wrapInline("_{", content, "}")
+	}
+	if inline.Link != "" {
+		content = wrapInline("[["+inline.Link+"][", content, "]]")
+	}
+
+	return content
+}
+
+func (r *orgRenderer) End() string {
+	r.block()
+
+	if len(r.footnotes) > 0 {
+		r.out.WriteString("* Footnotes\n\n" + strings.Join(r.footnotes, "\n") + "\n")
+	}
+
+	return fmt.Sprintf("#+TITLE: %s\n\n", r.title) + r.out.String()
+}
@@ -0,0 +1,194 @@
+package conversion
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"google.golang.org/api/docs/v1"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	// presetColorKeys are the style fields holding an OptionalColor, which presets may write as hex
+	presetColorKeys = map[string]bool{"backgroundColor": true, "color": true, "foregroundColor": true}
+
+	// presetDimensionKeys are the style fields holding a Dimension, which presets may write as points
+	presetDimensionKeys = map[string]bool{
+		"fontSize":        true,
+		"indentEnd":       true,
+		"indentFirstLine": true,
+		"indentStart":     true,
+		"padding":         true,
+		"spaceAbove":      true,
+		"spaceBelow":      true,
+		"width":           true,
+	}
+)
+
+// Preset is a named combination of a text style and a paragraph style, along
+// with the fields each one sets
+type Preset struct {
+	ParagraphFields []string
+	ParagraphStyle  *docs.ParagraphStyle
+	TextFields      []string
+	TextStyle       *docs.TextStyle
+}
+
+// presetStyles is a preset as written in a presets file
+type presetStyles struct {
+	ParagraphStyle map[string]any `yaml:"paragraphStyle"`
+	TextStyle      map[string]any `yaml:"textStyle"`
+}
+
+// ParsePresets parses a YAML map of preset names to a textStyle and a
+// paragraphStyle in the API's format. Colors may be written as hex strings and
+// dimensions as numbers of points.
+func ParsePresets(data []byte) (map[string]*Preset, error) {
+	var raw map[string]presetStyles
+
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&raw); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("error parsing presets: %w", err)
+	}
+
+	presets := map[string]*Preset{}
+	for name, styles := range raw {
+		preset := &Preset{
+			ParagraphStyle: &docs.ParagraphStyle{},
+			TextStyle:      &docs.TextStyle{},
+		}
+
+		var err error
+		preset.ParagraphFields, preset.ParagraphStyle.ForceSendFields, err = decodePresetStyle(styles.ParagraphStyle, preset.ParagraphStyle)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing preset %s: paragraphStyle: %w", name, err)
+		}
+
+		preset.TextFields, preset.TextStyle.ForceSendFields, err = decodePresetStyle(styles.TextStyle, preset.TextStyle)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing preset %s: textStyle: %w", name, err)
+		}
+
+		if len(preset.ParagraphFields) == 0 && len(preset.TextFields) == 0 {
+			return nil, fmt.Errorf("error parsing preset %s: no textStyle or paragraphStyle fields", name)
+		}
+
+		presets[name] = preset
+	}
+
+	return presets, nil
+}
+
+// decodePresetStyle decodes a style map into a text or paragraph style,
+// returning the fields it sets and the Go names of those set to zero values,
+// which must be sent explicitly
+func decodePresetStyle(values map[string]any, style any) ([]string, []string, error) {
+	if len(values) == 0 {
+		return nil, nil, nil
+	}
+
+	var fields []string
+	var forceSend []string
+	for key, value := range values {
+		fields = append(fields, key)
+
+		zero := false
+		switch v := value.(type) {
+		case bool:
+			zero = !v
+		case float64:
+			zero = v == 0
+		case int:
+			zero = v == 0
+		}
+		if zero {
+			forceSend = append(forceSend, strings.ToUpper(key[:1])+key[1:])
+		}
+	}
+	sort.Strings(fields)
+	sort.Strings(forceSend)
+
+	expanded, err := expandPresetValue("", values)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	data, err := json.Marshal(expanded)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(style); err != nil {
+		return nil, nil, err
+	}
+
+	return fields, forceSend, nil
+}
+
+// expandPresetValue expands hex colors and point dimensions to the API's format
+func expandPresetValue(key string, value any) (any, error) {
+	switch v := value.(type) {
+	case map[string]any:
+		expanded := map[string]any{}
+		for k, child := range v {
+			var err error
+			if expanded[k], err = expandPresetValue(k, child); err != nil {
+				return nil, err
+			}
+		}
+		return expanded, nil
+	case string:
+		if presetColorKeys[key] {
+			color := ParseColor(v)
+			if color == nil {
+				return nil, fmt.Errorf("invalid color for %s: %s (must be a hex color like #FF0000)", key, v)
+			}
+			return color, nil
+		}
+	case int:
+		if presetDimensionKeys[key] {
+			return &docs.Dimension{Magnitude: float64(v), Unit: "PT"}, nil
+		}
+	case float64:
+		if presetDimensionKeys[key] {
+			return &docs.Dimension{Magnitude: v, Unit: "PT"}, nil
+		}
+	}
+
+	return value, nil
+}
+
+// Requests returns the requests applying the preset to a range
+func (p *Preset) Requests(r *docs.Range) []*docs.Request {
+	var requests []*docs.Request
+
+	if len(p.ParagraphFields) > 0 {
+		requests = append(requests, &docs.Request{
+			UpdateParagraphStyle: &docs.UpdateParagraphStyleRequest{
+				Fields:         strings.Join(p.ParagraphFields, ","),
+				ParagraphStyle: p.ParagraphStyle,
+				Range:          r,
+			},
+		})
+	}
+
+	if len(p.TextFields) > 0 {
+		requests = append(requests, &docs.Request{
+			UpdateTextStyle: &docs.UpdateTextStyleRequest{
+				Fields:    strings.Join(p.TextFields, ","),
+				Range:     r,
+				TextStyle: p.TextStyle,
+			},
+		})
+	}
+
+	return requests
+}
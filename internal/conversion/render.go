@@ -0,0 +1,503 @@
+package conversion
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"google.golang.org/api/docs/v1"
+)
+
+// Inline kinds
+const (
+	InlineEquation = "equation"
+	InlineFootnote = "footnote"
+	InlineImage    = "image"
+	InlineRule     = "rule"
+	InlineText     = "text"
+)
+
+// Inline is a run of inline content handed to a Renderer
+type Inline struct {
+	Alt            string
+	Baseline       string
+	Bold           bool
+	Code           bool
+	Deleted        bool
+	FootnoteID     string
+	FootnoteNumber string
+	Inserted       bool
+	Italic         bool
+	Kind           string
+	Link           string
+	Path           string
+	Person         bool
+	Strikethrough  bool
+	Text           string
+	Title          string
+	Underline      bool
+}
+
+// Table is a table whose cells are already rendered inline content
+type Table struct {
+	// Callout is set for a single shaded cell, the usual way to draw a note box in Docs
+	Callout bool
+	Rows    [][]string
+}
+
+// Renderer produces one output format. Render walks the document once and
+// calls the block methods in reading order; block methods receive content
+// already rendered through Inline.
+type Renderer interface {
+	Begin(title string)
+	Break()
+	CodeBlock(lines []string)
+	End() string
+	Footnote(id string, number string, text string)
+	Heading(level int, id string, text string)
+	Inline(inline Inline) string
+	ListItem(level int, ordered bool, text string)
+	Paragraph(text string)
+	Table(table Table)
+	TableOfContents()
+}
+
+// RenderOptions controls optional parts of the rendered output
+type RenderOptions struct {
+	// Assets maps inline and positioned object IDs to the local file referenced from the image
+	Assets map[string]string
+	// Comments are rendered as annotations next to the text they are anchored to
+	Comments []Comment
+}
+
+// Comment is a document comment anchored to a piece of text
+type Comment struct {
+	Author  string
+	Content string
+	Quoted  string
+}
+
+var renderers = map[string]func(opts RenderOptions) Renderer{
+	"asciidoc":   func(opts RenderOptions) Renderer { return &asciidocRenderer{} },
+	"confluence": func(opts RenderOptions) Renderer { return newConfluenceRenderer() },
+	"html":       func(opts RenderOptions) Renderer { return newHTMLRenderer() },
+	"jira":       func(opts RenderOptions) Renderer { return &jiraRenderer{} },
+	"latex":      func(opts RenderOptions) Renderer { return &latexRenderer{} },
+	"markdown":   func(opts RenderOptions) Renderer { return &markdownRenderer{} },
+	"org":        func(opts RenderOptions) Renderer { return &orgRenderer{} },
+	"rst":        func(opts RenderOptions) Renderer { return &rstRenderer{} },
+}
+
+var formatAliases = map[string]string{
+	"adoc": "asciidoc",
+	"md":   "markdown",
+	"tex":  "latex",
+}
+
+// Formats returns the names of the supported output formats
+func Formats() []string {
+	formats := make([]string, 0, len(renderers))
+	for name := range renderers {
+		formats = append(formats, name)
+	}
+	sort.Strings(formats)
+	return formats
+}
+
+// NewRenderer returns the renderer for a format name
+func NewRenderer(format string, opts RenderOptions) (Renderer, error) {
+	format = strings.ToLower(format)
+	if alias, ok := formatAliases[format]; ok {
+		format = alias
+	}
+
+	newRenderer, ok := renderers[format]
+	if !ok {
+		return nil, fmt.Errorf("invalid format: %s (must be one of %s)", format, strings.Join(Formats(), ", "))
+	}
+	return newRenderer(opts), nil
+}
+
+// Render walks a document once, feeding its content to r, and returns the output
+func Render(doc *docs.Document, r Renderer, opts RenderOptions) string {
+	w := &walker{doc: doc, opts: opts, r: r, seen: map[string]bool{}}
+
+	annotator, annotates := r.(commentAnnotator)
+	if annotates {
+		w.anchors = resolveComments(doc, opts.Comments)
+	}
+
+	r.Begin(doc.Title)
+	w.content(doc.Body.Content)
+	w.flushCode()
+
+	for _, footnote := range w.footnotes {
+		r.Footnote(footnote.FootnoteID, footnote.FootnoteNumber, footnote.Text)
+	}
+
+	// Comments whose text was not found or not rendered, such as in code blocks
+	for _, anchor := range w.anchors {
+		if anchor.done {
+			continue
+		}
+		if note := annotator.Annotate(anchor.comment, "", true); note != "" {
+			r.Paragraph(note)
+		}
+	}
+
+	return r.End()
+}
+
+type walker struct {
+	anchors   []*commentAnchor
+	code      []string
+	doc       *docs.Document
+	footnotes []Inline
+	opts      RenderOptions
+	r         Renderer
+	seen      map[string]bool
+}
+
+func (w *walker) content(content []*docs.StructuralElement) {
+	for _, element := range content {
+		switch {
+		case element.Paragraph != nil:
+			w.paragraph(element.Paragraph)
+		case element.Table != nil:
+			w.flushCode()
+			w.r.Table(w.table(element.Table))
+		case element.TableOfContents != nil:
+			w.flushCode()
+			w.r.TableOfContents()
+		case element.SectionBreak != nil && element.StartIndex > 0:
+			// Every body starts with a section break at index 0, which is not rendered
+			w.flushCode()
+			w.r.Break()
+		}
+	}
+}
+
+func (w *walker) paragraph(paragraph *docs.Paragraph) {
+	level := -1
+	headingID := ""
+	if style := paragraph.ParagraphStyle; style != nil {
+		level = HeadingLevel(style.NamedStyleType)
+		headingID = style.HeadingId
+	}
+
+	if level < 0 && paragraph.Bullet == nil && w.isCodeParagraph(paragraph) {
+		w.code = append(w.code, strings.TrimSuffix(rawParagraphText(paragraph), "\n"))
+		return
+	}
+	w.flushCode()
+
+	text := w.inlines(paragraph.Elements, level >= 0)
+
+	switch {
+	case level >= 0:
+		if strings.TrimSpace(text) != "" {
+			w.r.Heading(level, headingID, text)
+		}
+	case paragraph.Bullet != nil:
+		if IsChecklist(w.doc, paragraph.Bullet) {
+			text = checkboxMarker(IsChecked(paragraph)) + text
+		}
+		w.r.ListItem(int(paragraph.Bullet.NestingLevel), IsOrderedList(w.doc, paragraph.Bullet), text)
+	case strings.TrimSpace(text) != "":
+		w.r.Paragraph(text)
+	}
+
+	for _, objectID := range paragraph.PositionedObjectIds {
+		if image := w.positionedImage(objectID); image.Path != "" {
+			w.r.Paragraph(w.r.Inline(image))
+		}
+	}
+}
+
+// isCodeParagraph reports whether a paragraph is entirely set in a code font.
+// Blank lines only count as code when they continue an open code block.
+func (w *walker) isCodeParagraph(paragraph *docs.Paragraph) bool {
+	hasText := false
+
+	for _, element := range paragraph.Elements {
+		if element.TextRun == nil {
+			return false
+		}
+		if element.TextRun.TextStyle == nil || !IsMonospace(element.TextRun.TextStyle) {
+			if strings.TrimSpace(element.TextRun.Content) != "" {
+				return false
+			}
+			continue
+		}
+		if strings.TrimSpace(element.TextRun.Content) != "" {
+			hasText = true
+		}
+	}
+
+	return hasText || len(w.code) > 0 && strings.TrimSpace(rawParagraphText(paragraph)) == ""
+}
+
+func (w *walker) flushCode() {
+	if len(w.code) == 0 {
+		return
+	}
+
+	lines := w.code
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+	w.code = nil
+
+	if len(lines) > 0 {
+		w.r.CodeBlock(lines)
+	}
+}
+
+// inlines renders paragraph elements. Text anchoring a comment is rendered
+// separately from the rest of its run and annotated as a whole.
+func (w *walker) inlines(elements []*docs.ParagraphElement, heading bool) string {
+	var text, annotated strings.Builder
+	var open *commentAnchor
+	var openEnd int64
+
+	flush := func() {
+		if open == nil {
+			return
+		}
+		last := openEnd >= open.endIndex
+		text.WriteString(w.r.(commentAnnotator).Annotate(open.comment, annotated.String(), last))
+		open.done = open.done || last
+		annotated.Reset()
+		open = nil
+	}
+
+	write := func(rendered string, start int64, end int64) {
+		anchor := anchorAt(w.anchors, start, end)
+		if anchor != open {
+			flush()
+		}
+		if anchor == nil {
+			text.WriteString(rendered)
+			return
+		}
+		open, openEnd = anchor, end
+		annotated.WriteString(rendered)
+	}
+
+	for _, element := range elements {
+		var inline Inline
+
+		switch {
+		case element.TextRun != nil:
+			content := strings.TrimSuffix(element.TextRun.Content, "\n")
+			if content == "" {
+				continue
+			}
+			inline = textInline(content, element.TextRun.TextStyle)
+			inline.Inserted = len(element.TextRun.SuggestedInsertionIds) > 0
+			inline.Deleted = len(element.TextRun.SuggestedDeletionIds) > 0
+			if heading {
+				// Headings are bold through their named style; explicit bold adds nothing
+				inline.Bold = false
+			}
+
+			for _, piece := range splitAtAnchors(content, element.StartIndex, w.anchors) {
+				pieceInline := inline
+				pieceInline.Text = piece.text
+				write(w.r.Inline(pieceInline), piece.start, piece.end)
+			}
+			continue
+		case element.InlineObjectElement != nil:
+			objectID := element.InlineObjectElement.InlineObjectId
+			inlineObject, ok := w.doc.InlineObjects[objectID]
+			if !ok || inlineObject.InlineObjectProperties == nil {
+				continue
+			}
+			inline = w.image(inlineObject.InlineObjectProperties.EmbeddedObject, objectID)
+			if inline.Path == "" {
+				continue
+			}
+		case element.Person != nil && element.Person.PersonProperties != nil:
+			props := element.Person.PersonProperties
+			inline = Inline{Kind: InlineText, Link: "mailto:" + props.Email, Person: true, Text: props.Name}
+			if inline.Text == "" {
+				inline.Text = props.Email
+			}
+		case element.RichLink != nil && element.RichLink.RichLinkProperties != nil:
+			props := element.RichLink.RichLinkProperties
+			inline = Inline{Kind: InlineText, Link: props.Uri, Text: props.Title}
+			if inline.Text == "" {
+				inline.Text = props.Uri
+			}
+		case element.FootnoteReference != nil:
+			inline = w.footnote(element.FootnoteReference)
+		case element.Equation != nil:
+			inline = Inline{Kind: InlineEquation, Text: equationPlaceholder}
+		case element.HorizontalRule != nil:
+			inline = Inline{Kind: InlineRule}
+		default:
+			continue
+		}
+
+		write(w.r.Inline(inline), element.StartIndex, element.EndIndex)
+	}
+	flush()
+
+	return text.String()
+}
+
+func textInline(content string, style *docs.TextStyle) Inline {
+	inline := Inline{Kind: InlineText, Text: content}
+	if style == nil {
+		return inline
+	}
+
+	inline.Bold = style.Bold
+	inline.Code = IsMonospace(style)
+	inline.Italic = style.Italic
+	inline.Strikethrough = style.Strikethrough
+	inline.Underline = style.Underline && style.Link == nil
+
+	switch style.BaselineOffset {
+	case "SUPERSCRIPT", "SUBSCRIPT":
+		inline.Baseline = style.BaselineOffset
+	}
+
+	if style.Link != nil {
+		inline.Link = LinkTarget(style.Link)
+	}
+
+	return inline
+}
+
+func (w *walker) image(object *docs.EmbeddedObject, objectID string) Inline {
+	inline := Inline{Kind: InlineImage, Path: w.opts.Assets[objectID]}
+	if object == nil {
+		return inline
+	}
+
+	inline.Alt = object.Title
+	if inline.Alt == "" {
+		inline.Alt = object.Description
+	}
+	if object.Description != inline.Alt {
+		inline.Title = object.Description
+	}
+
+	return inline
+}
+
+// positionedImage returns the image of a positioned object, without a path when
+// the object is unknown or was not exported
+func (w *walker) positionedImage(objectID string) Inline {
+	positionedObject, ok := w.doc.PositionedObjects[objectID]
+	if !ok || positionedObject.PositionedObjectProperties == nil {
+		return Inline{Kind: InlineImage}
+	}
+	return w.image(positionedObject.PositionedObjectProperties.EmbeddedObject, objectID)
+}
+
+func (w *walker) footnote(ref *docs.FootnoteReference) Inline {
+	inline := Inline{
+		FootnoteID:     ref.FootnoteId,
+		FootnoteNumber: ref.FootnoteNumber,
+		Kind:           InlineFootnote,
+	}
+	if inline.FootnoteNumber == "" {
+		inline.FootnoteNumber = fmt.Sprintf("%d", len(w.footnotes)+1)
+	}
+
+	if footnote, ok := w.doc.Footnotes[ref.FootnoteId]; ok {
+		// Footnote indices are relative to the footnote, so body anchors do not apply
+		anchors := w.anchors
+		w.anchors = nil
+		defer func() { w.anchors = anchors }()
+
+		var parts []string
+		for _, element := range footnote.Content {
+			if element.Paragraph != nil {
+				if text := strings.TrimSpace(w.inlines(element.Paragraph.Elements, false)); text != "" {
+					parts = append(parts, text)
+				}
+			}
+		}
+		inline.Text = strings.Join(parts, " ")
+	}
+
+	if !w.seen[ref.FootnoteId] {
+		w.seen[ref.FootnoteId] = true
+		w.footnotes = append(w.footnotes, inline)
+	}
+
+	return inline
+}
+
+func (w *walker) table(table *docs.Table) Table {
+	var result Table
+
+	for _, row := range table.TableRows {
+		cells := make([]string, 0, len(row.TableCells))
+		for _, cell := range row.TableCells {
+			var parts []string
+			for _, element := range cell.Content {
+				if element.Paragraph == nil {
+					continue
+				}
+				if text := strings.TrimSpace(w.inlines(element.Paragraph.Elements, false)); text != "" {
+					parts = append(parts, text)
+				}
+				// Positioned images have no place in a cell's text, so they follow it
+				for _, objectID := range element.Paragraph.PositionedObjectIds {
+					if image := w.positionedImage(objectID); image.Path != "" {
+						parts = append(parts, w.r.Inline(image))
+					}
+				}
+			}
+			cells = append(cells, strings.Join(parts, " "))
+		}
+		result.Rows = append(result.Rows, cells)
+	}
+
+	if len(table.TableRows) == 1 && len(table.TableRows[0].TableCells) == 1 {
+		style := table.TableRows[0].TableCells[0].TableCellStyle
+		result.Callout = style != nil && style.BackgroundColor != nil && style.BackgroundColor.Color != nil
+	}
+
+	return result
+}
+
+// HeadingLevel maps a named style to a heading level: 0 for TITLE, 1-6 for
+// HEADING_1 to HEADING_6, and -1 for any other style
+func HeadingLevel(namedStyleType string) int {
+	if namedStyleType == "TITLE" {
+		return 0
+	}
+
+	level := 0
+	if _, err := fmt.Sscanf(namedStyleType, "HEADING_%d", &level); err != nil || level < 1 {
+		return -1
+	}
+	return level
+}
+
+func rawParagraphText(paragraph *docs.Paragraph) string {
+	var text strings.Builder
+	for _, element := range paragraph.Elements {
+		if element.TextRun != nil {
+			text.WriteString(element.TextRun.Content)
+		}
+	}
+	return text.String()
+}
+
+// wrapInline wraps text in markers, keeping surrounding spaces outside
+func wrapInline(open string, text string, close string) string {
+	trimmed := strings.TrimSpace(text)
+	if trimmed == "" {
+		return text
+	}
+
+	leading := text[:strings.Index(text, trimmed)]
+	trailing := text[len(leading)+len(trimmed):]
+	return leading + open + trimmed + close + trailing
+}
@@ -0,0 +1,209 @@
+package conversion
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"unicode/utf16"
+
+	"google.golang.org/api/docs/v1"
+)
+
+var update = flag.Bool("update", false, "Rewrite the golden files with the current output")
+
+// goldenExtensions maps each output format to the extension of its golden file
+var goldenExtensions = map[string]string{
+	"asciidoc":   ".adoc",
+	"confluence": ".xml",
+	"html":       ".html",
+	"jira":       ".jira",
+	"latex":      ".tex",
+	"markdown":   ".md",
+	"org":        ".org",
+	"rst":        ".rst",
+}
+
+func TestRenderGolden(t *testing.T) {
+	for _, format := range Formats() {
+		t.Run(format, func(t *testing.T) {
+			ext, ok := goldenExtensions[format]
+			if !ok {
+				t.Fatalf("no golden file for format %s", format)
+			}
+
+			renderer, err := NewRenderer(format, RenderOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := Render(goldenDocument(), renderer, RenderOptions{})
+
+			path := filepath.Join("testdata", "document"+ext)
+			if *update {
+				if err := os.WriteFile(path, []byte(got), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			want, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != string(want) {
+				t.Errorf("output differs from %s (run go test -update to rewrite it):\n%s", path, got)
+			}
+		})
+	}
+}
+
+// goldenBuilder lays out body content, assigning document indices in order
+type goldenBuilder struct {
+	content []*docs.StructuralElement
+	index   int64
+}
+
+func (b *goldenBuilder) paragraph(style *docs.ParagraphStyle, bullet *docs.Bullet, elements ...*docs.ParagraphElement) *docs.StructuralElement {
+	element := &docs.StructuralElement{
+		Paragraph:  &docs.Paragraph{Bullet: bullet, ParagraphStyle: style},
+		StartIndex: b.index,
+	}
+
+	elements = append(elements, text("\n", nil))
+	for _, e := range elements {
+		e.StartIndex = b.index
+		if e.TextRun != nil {
+			b.index += int64(len(utf16.Encode([]rune(e.TextRun.Content))))
+		} else {
+			b.index++
+		}
+		e.EndIndex = b.index
+	}
+
+	element.Paragraph.Elements = elements
+	element.EndIndex = b.index
+	return element
+}
+
+func (b *goldenBuilder) add(style string, elements ...*docs.ParagraphElement) {
+	b.content = append(b.content, b.paragraph(&docs.ParagraphStyle{NamedStyleType: style}, nil, elements...))
+}
+
+func (b *goldenBuilder) heading(style string, id string, title string) {
+	b.content = append(b.content, b.paragraph(&docs.ParagraphStyle{HeadingId: id, NamedStyleType: style}, nil, text(title, nil)))
+}
+
+func (b *goldenBuilder) item(listID string, level int64, elements ...*docs.ParagraphElement) {
+	bullet := &docs.Bullet{ListId: listID, NestingLevel: level}
+	b.content = append(b.content, b.paragraph(&docs.ParagraphStyle{NamedStyleType: "NORMAL_TEXT"}, bullet, elements...))
+}
+
+func (b *goldenBuilder) table(rows ...[]string) {
+	table := &docs.Table{Columns: int64(len(rows[0])), Rows: int64(len(rows))}
+	start := b.index
+	b.index++
+
+	for _, cells := range rows {
+		row := &docs.TableRow{}
+		b.index++
+		for _, cell := range cells {
+			b.index++
+			row.TableCells = append(row.TableCells, &docs.TableCell{
+				Content: []*docs.StructuralElement{b.paragraph(nil, nil, text(cell, nil))},
+			})
+		}
+		table.TableRows = append(table.TableRows, row)
+	}
+
+	b.index++
+	b.content = append(b.content, &docs.StructuralElement{EndIndex: b.index, StartIndex: start, Table: table})
+}
+
+func text(content string, style *docs.TextStyle) *docs.ParagraphElement {
+	return &docs.ParagraphElement{TextRun: &docs.TextRun{Content: content, TextStyle: style}}
+}
+
+func code() *docs.TextStyle {
+	return &docs.TextStyle{WeightedFontFamily: &docs.WeightedFontFamily{FontFamily: "Courier New"}}
+}
+
+// goldenDocument covers headings of every level, nested bulleted and numbered
+// lists, a table, URL and heading links, emphasis, inline code, a code block,
+// a footnote, a person chip, baseline offsets and text full of characters that
+// are markup in one format or another, which must come out literally
+func goldenDocument() *docs.Document {
+	b := &goldenBuilder{
+		content: []*docs.StructuralElement{{EndIndex: 1, SectionBreak: &docs.SectionBreak{}}},
+		index:   1,
+	}
+
+	b.heading("TITLE", "", "Release Guide")
+	b.add("NORMAL_TEXT",
+		text("This guide is ", nil),
+		text("important", &docs.TextStyle{Bold: true}),
+		text(", ", nil),
+		text("recent", &docs.TextStyle{Italic: true}),
+		text(" and ", nil),
+		text("final", &docs.TextStyle{Strikethrough: true}),
+		text(". Run ", nil),
+		text("make release", code()),
+		text(" first.", nil),
+		&docs.ParagraphElement{FootnoteReference: &docs.FootnoteReference{FootnoteId: "fn.1", FootnoteNumber: "1"}},
+	)
+
+	b.heading("HEADING_1", "h.intro", "Introduction")
+	b.add("NORMAL_TEXT",
+		text("See the ", nil),
+		text("website", &docs.TextStyle{Link: &docs.Link{Url: "https://example.com/docs"}}),
+		text(" or the ", nil),
+		text("introduction", &docs.TextStyle{Link: &docs.Link{HeadingId: "h.intro"}}),
+		text(".", nil),
+	)
+
+	b.heading("HEADING_2", "", "Steps")
+	b.item("list.numbers", 0, text("Build", nil))
+	b.item("list.numbers", 1, text("Compile the ", nil), text("sources", &docs.TextStyle{Bold: true}))
+	b.item("list.numbers", 1, text("Run the tests", nil))
+	b.item("list.numbers", 0, text("Publish", nil))
+
+	b.heading("HEADING_3", "", "Checks")
+	b.item("list.bullets", 0, text("Changelog", nil))
+	b.item("list.bullets", 1, text("Version bumped", nil))
+	b.item("list.bullets", 0, text("Tag pushed", nil))
+
+	b.heading("HEADING_4", "", "Matrix")
+	b.table([]string{"Platform", "Status"}, []string{"Linux", "Ready"}, []string{"macOS", "Pending"})
+
+	b.heading("HEADING_5", "", "Script")
+	b.add("NORMAL_TEXT", text("make test", code()))
+	b.add("NORMAL_TEXT", text("make release", code()))
+
+	b.heading("HEADING_6", "", "Notes")
+	b.add("NORMAL_TEXT", text("Done.", nil))
+	b.add("NORMAL_TEXT",
+		text("Ask ", nil),
+		&docs.ParagraphElement{Person: &docs.Person{PersonProperties: &docs.PersonProperties{Email: "ada@example.com", Name: "Ada"}}},
+		text(" about ", nil),
+		text("x", &docs.TextStyle{Underline: true}),
+		text("2", &docs.TextStyle{BaselineOffset: "SUPERSCRIPT"}),
+		text(" and H", nil),
+		text("2", &docs.TextStyle{BaselineOffset: "SUBSCRIPT"}),
+		text("O.", nil),
+	)
+	b.add("NORMAL_TEXT", text(`Keep a_b * c + [d] | {e} ~f^ -g! & <h> #1 50% \ literal`, nil))
+
+	footnote := &goldenBuilder{}
+	footnote.add("NORMAL_TEXT", text(" Releases are cut on Mondays.", nil))
+
+	bullets := &docs.ListProperties{NestingLevels: []*docs.NestingLevel{{GlyphSymbol: "●"}, {GlyphSymbol: "○"}}}
+	numbers := &docs.ListProperties{NestingLevels: []*docs.NestingLevel{{GlyphType: "DECIMAL"}, {GlyphType: "ALPHA"}}}
+
+	return &docs.Document{
+		Body:      &docs.Body{Content: b.content},
+		Footnotes: map[string]docs.Footnote{"fn.1": {Content: footnote.content, FootnoteId: "fn.1"}},
+		Lists: map[string]docs.List{
+			"list.bullets": {ListProperties: bullets},
+			"list.numbers": {ListProperties: numbers},
+		},
+		Title: "release-guide",
+	}
+}
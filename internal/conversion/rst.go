@@ -0,0 +1,207 @@
+package conversion
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// rstAdornments are the underline characters of HEADING_1 to HEADING_6; the
+// title is overlined and underlined with "=", a style of its own
+var rstAdornments = []string{"=", "-", "~", "^", "\"", "'"}
+
+// rstEscaper protects characters that start inline markup in plain text
+var rstEscaper = strings.NewReplacer(`\`, `\\`, "*", `\*`, "`", "\\`", "_", `\_`, "|", `\|`)
+
+// rstRenderer renders reStructuredText. Inline markup cannot nest in rST, so
+// links keep their text plain.
+type rstRenderer struct {
+	footnotes []string
+	images    []string
+	lists     []int
+	out       strings.Builder
+	// struck is set once strikethrough is used, which needs a custom role
+	struck bool
+	title  string
+	titled bool
+}
+
+// Begin records the title; the header is written by End, once it is known
+// whether a TITLE paragraph replaces it
+func (r *rstRenderer) Begin(title string) {
+	r.title = title
+}
+
+// block ends any open list, which rST requires to be followed by a blank line
+func (r *rstRenderer) block() {
+	if len(r.lists) > 0 {
+		r.out.WriteString("\n")
+		r.lists = nil
+	}
+}
+
+// Heading maps TITLE to the document title, or to a title-styled section once
+// content precedes it, and HEADING_n to the nth underline style
+func (r *rstRenderer) Heading(level int, id string, text string) {
+	r.block()
+	if level == 0 && r.out.Len() == 0 && !r.titled {
+		r.title, r.titled = text, true
+		return
+	}
+
+	if id != "" {
+		r.out.WriteString(fmt.Sprintf(".. _%s:\n\n", id))
+	}
+	if level == 0 {
+		r.out.WriteString(rstTitle(text))
+		return
+	}
+	adornment := rstAdornments[min(level, len(rstAdornments))-1]
+	r.out.WriteString(fmt.Sprintf("%s\n%s\n\n", text, strings.Repeat(adornment, max(utf8.RuneCountInString(text), 1))))
+}
+
+// rstTitle overlines and underlines a title
+func rstTitle(title string) string {
+	rule := strings.Repeat("=", max(utf8.RuneCountInString(title), 1))
+	return fmt.Sprintf("%s\n%s\n%s\n\n", rule, title, rule)
+}
+
+func (r *rstRenderer) Paragraph(text string) {
+	r.block()
+	r.out.WriteString(text + "\n\n")
+}
+
+// ListItem indents nested items under the text of their parent item and
+// separates nesting changes with blank lines, as rST requires
+func (r *rstRenderer) ListItem(level int, ordered bool, text string) {
+	marker := "-"
+	if ordered {
+		marker = "#."
+	}
+
+	switch {
+	case level+1 > len(r.lists):
+		if len(r.lists) > 0 {
+			r.out.WriteString("\n")
+		}
+		for len(r.lists) < level+1 {
+			r.lists = append(r.lists, len(marker)+1)
+		}
+	case level+1 < len(r.lists):
+		r.lists = r.lists[:level+1]
+		r.out.WriteString("\n")
+	}
+	r.lists[level] = len(marker) + 1
+
+	indent := 0
+	for _, width := range r.lists[:level] {
+		indent += width
+	}
+	r.out.WriteString(fmt.Sprintf("%s%s %s\n", strings.Repeat(" ", indent), marker, text))
+}
+
+func (r *rstRenderer) Table(table Table) {
+	r.block()
+	r.out.WriteString(".. list-table::\n   :header-rows: 1\n\n")
+	for _, row := range table.Rows {
+		for i, cell := range row {
+			prefix := "     "
+			if i == 0 {
+				prefix = "   * "
+			}
+			r.out.WriteString(strings.TrimRight(prefix+"- "+cell, " ") + "\n")
+		}
+	}
+	r.out.WriteString("\n")
+}
+
+func (r *rstRenderer) CodeBlock(lines []string) {
+	r.block()
+	r.out.WriteString("::\n\n")
+	for _, line := range lines {
+		r.out.WriteString(strings.TrimRight("    "+line, " ") + "\n")
+	}
+	r.out.WriteString("\n")
+}
+
+func (r *rstRenderer) Break() {
+	r.block()
+	r.out.WriteString("----------\n\n")
+}
+
+func (r *rstRenderer) TableOfContents() {
+	r.block()
+	r.out.WriteString(".. contents::\n\n")
+}
+
+func (r *rstRenderer) Footnote(id string, number string, text string) {
+	r.footnotes = append(r.footnotes, fmt.Sprintf(".. [#fn-%s] %s", number, text))
+}
+
+func (r *rstRenderer) Inline(inline Inline) string {
+	switch inline.Kind {
+	case InlineImage:
+		// Images are inline substitutions defined at the end of the document
+		name := fmt.Sprintf("image%d", len(r.images)+1)
+		r.images = append(r.images, fmt.Sprintf(".. |%s| image:: %s\n   :alt: %s", name, inline.Path, inline.Alt))
+		return "|" + name + "|"
+	case InlineFootnote:
+		// The escaped space lets the reference follow a word without adding a space
+		return fmt.Sprintf("\\ [#fn-%s]_", inline.FootnoteNumber)
+	case InlineEquation:
+		return "``" + inline.Text + "``"
+	case InlineRule:
+		return "----------"
+	}
+
+	if inline.Link != "" {
+		target := inline.Link
+		if strings.HasPrefix(target, "#") {
+			target = strings.TrimPrefix(target, "#") + "_"
+		}
+		return wrapInline("`", rstEscaper.Replace(inline.Text), " <"+target+">`__")
+	}
+
+	content := rstEscaper.Replace(inline.Text)
+	switch {
+	case inline.Code:
+		// Inline literals are not parsed, so the text is kept as is
+		content = wrapInline("``", inline.Text, "``")
+	case inline.Bold:
+		content = wrapInline("**", content, "**")
+	case inline.Italic:
+		content = wrapInline("*", content, "*")
+	case inline.Strikethrough:
+		r.struck = true
+		content = wrapInline(":del:`", content, "`")
+	case inline.Baseline == "SUPERSCRIPT":
+		content = wrapInline(":sup:`", content, "`")
+	case inline.Baseline == "SUBSCRIPT":
+		content = wrapInline(":sub:`", content, "`")
+	}
+
+	return content
+}
+
+func (r *rstRenderer) End() string {
+	r.block()
+
+	body := r.out.String()
+	r.out.Reset()
+	r.out.WriteString(rstTitle(r.title))
+	if r.struck {
+		// Roles must be declared before their first use; the del class can be
+		// styled as struck-through text by the HTML writer's stylesheet
+		r.out.WriteString(".. role:: del\n\n")
+	}
+	r.out.WriteString(body)
+
+	if len(r.footnotes) > 0 {
+		r.out.WriteString(".. rubric:: Footnotes\n\n" + strings.Join(r.footnotes, "\n") + "\n\n")
+	}
+	if len(r.images) > 0 {
+		r.out.WriteString(strings.Join(r.images, "\n") + "\n")
+	}
+
+	return r.out.String()
+}
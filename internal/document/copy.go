@@ -0,0 +1,490 @@
+package document
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf16"
+
+	"google-docs-manager/internal/conversion"
+	"google.golang.org/api/docs/v1"
+)
+
+const (
+	// ParagraphStyleFields lists every settable paragraph style field. Updating
+	// with this mask resets the fields a style leaves unset to its named style.
+	ParagraphStyleFields = "alignment,avoidWidowAndOrphan,borderBetween,borderBottom,borderLeft,borderRight,borderTop,direction,indentEnd,indentFirstLine,indentStart,keepLinesTogether,keepWithNext,lineSpacing,namedStyleType,pageBreakBefore,shading,spaceAbove,spaceBelow,spacingMode"
+
+	// TextStyleFields lists every settable text style field
+	TextStyleFields = "backgroundColor,baselineOffset,bold,fontSize,foregroundColor,italic,link,smallCaps,strikethrough,underline,weightedFontFamily"
+
+	// objectPlaceholder reserves the single index of a chip, image or page break
+	// until it is replaced
+	objectPlaceholder = "￼"
+)
+
+// ElementsInRange returns the structural elements lying entirely within
+// [startIndex, endIndex)
+func ElementsInRange(content []*docs.StructuralElement, startIndex, endIndex int64) []*docs.StructuralElement {
+	var elements []*docs.StructuralElement
+	for _, element := range content {
+		if element.StartIndex >= startIndex && element.EndIndex <= endIndex {
+			elements = append(elements, element)
+		}
+	}
+	return elements
+}
+
+// ShiftHeadings returns the elements with headings demoted by offset levels,
+// titles becoming level offset headings. Levels stop at HEADING_6.
+func ShiftHeadings(elements []*docs.StructuralElement, offset int) []*docs.StructuralElement {
+	shifted := make([]*docs.StructuralElement, len(elements))
+	for i, element := range elements {
+		shifted[i] = element
+		if offset <= 0 || element.Paragraph == nil || element.Paragraph.ParagraphStyle == nil {
+			continue
+		}
+
+		level := conversion.HeadingLevel(element.Paragraph.ParagraphStyle.NamedStyleType)
+		if level < 0 {
+			continue
+		}
+
+		style := *element.Paragraph.ParagraphStyle
+		style.NamedStyleType = fmt.Sprintf("HEADING_%d", min(level+offset, 6))
+		paragraph := *element.Paragraph
+		paragraph.ParagraphStyle = &style
+		copied := *element
+		copied.Paragraph = &paragraph
+		shifted[i] = &copied
+	}
+	return shifted
+}
+
+// CopyElements returns the requests recreating structural elements of the
+// source document at index, in the same or another document, along with the
+// length of the inserted content. Paragraph and text styles, lists, tables,
+// inline images, person chips and page breaks are copied as they are; rich
+// links become linked text. Elements the API cannot create (equations,
+// footnotes, horizontal rules, positioned images and tables of contents) are
+// skipped, and like any inserted table or section break, copied ones are
+// preceded by an extra empty paragraph.
+func CopyElements(source *docs.Document, elements []*docs.StructuralElement, index int64) ([]*docs.Request, int64) {
+	c := &copier{index: index, source: source}
+	for _, element := range elements {
+		c.addElement(element)
+	}
+
+	// Creating bullets removes the leading tabs used for nesting, so lists are
+	// created last and from the end of the content to keep earlier indices valid
+	requests := c.requests
+	for i := len(c.lists) - 1; i >= 0; i-- {
+		requests = append(requests, c.lists[i])
+	}
+
+	return requests, c.index - index - c.tabs
+}
+
+type copier struct {
+	index    int64
+	lastList string
+	lists    []*docs.Request
+	requests []*docs.Request
+	source   *docs.Document
+	tabs     int64
+}
+
+func (c *copier) addElement(element *docs.StructuralElement) {
+	switch {
+	case element.Paragraph != nil:
+		c.addParagraph(element.Paragraph, true)
+	case element.Table != nil:
+		c.lastList = ""
+		c.addTable(element.Table)
+	case element.SectionBreak != nil:
+		c.lastList = ""
+		sectionType := "CONTINUOUS"
+		if element.SectionBreak.SectionStyle != nil && element.SectionBreak.SectionStyle.SectionType != "" {
+			sectionType = element.SectionBreak.SectionStyle.SectionType
+		}
+		c.requests = append(c.requests, &docs.Request{
+			InsertSectionBreak: &docs.InsertSectionBreakRequest{
+				Location:    &docs.Location{Index: c.index},
+				SectionType: sectionType,
+			},
+		})
+		// A newline is inserted before the section break itself
+		c.index += 2
+	}
+}
+
+// addParagraph copies a paragraph. Without newline, the paragraph takes over
+// the newline already at the insertion point, as in an empty table cell.
+func (c *copier) addParagraph(paragraph *docs.Paragraph, newline bool) {
+	start := c.index
+
+	prefix := ""
+	if paragraph.Bullet != nil {
+		prefix = strings.Repeat("\t", int(paragraph.Bullet.NestingLevel))
+	}
+
+	var text strings.Builder
+	var styles []*docs.Request
+	var objects []*docs.Request
+	text.WriteString(prefix)
+	offset := start + int64(len(prefix))
+
+	for _, element := range paragraph.Elements {
+		var content string
+		var style *docs.TextStyle
+
+		switch {
+		case element.TextRun != nil:
+			content = strings.TrimSuffix(element.TextRun.Content, "\n")
+			style = element.TextRun.TextStyle
+		case element.RichLink != nil && element.RichLink.RichLinkProperties != nil:
+			properties := element.RichLink.RichLinkProperties
+			content = properties.Title
+			if content == "" {
+				content = properties.Uri
+			}
+			style = &docs.TextStyle{Link: &docs.Link{Url: properties.Uri}}
+			if element.RichLink.TextStyle != nil {
+				linked := *element.RichLink.TextStyle
+				linked.Link = style.Link
+				style = &linked
+			}
+		case element.Person != nil && element.Person.PersonProperties != nil && element.Person.PersonProperties.Email != "":
+			content = objectPlaceholder
+			objects = append(objects, replacePlaceholder(offset, &docs.Request{
+				InsertPerson: &docs.InsertPersonRequest{
+					Location:         &docs.Location{Index: offset},
+					PersonProperties: &docs.PersonProperties{Email: element.Person.PersonProperties.Email},
+				},
+			})...)
+		case element.InlineObjectElement != nil:
+			insert := c.inlineImageRequest(element.InlineObjectElement.InlineObjectId, offset)
+			if insert == nil {
+				continue
+			}
+			content = objectPlaceholder
+			objects = append(objects, replacePlaceholder(offset, insert)...)
+		case element.PageBreak != nil && newline:
+			// A page break is inserted with a newline of its own, which is removed
+			content = objectPlaceholder
+			objects = append(objects, replacePlaceholder(offset, &docs.Request{
+				InsertPageBreak: &docs.InsertPageBreakRequest{
+					Location: &docs.Location{Index: offset},
+				},
+			})...)
+			objects = append(objects, &docs.Request{
+				DeleteContentRange: &docs.DeleteContentRangeRequest{
+					Range: &docs.Range{
+						EndIndex:   offset + 2,
+						StartIndex: offset + 1,
+					},
+				},
+			})
+		default:
+			continue
+		}
+
+		length := TextLength(content)
+		if length > 0 && style != nil {
+			styles = append(styles, &docs.Request{
+				UpdateTextStyle: &docs.UpdateTextStyleRequest{
+					Fields: TextStyleFields,
+					Range: &docs.Range{
+						EndIndex:   offset + length,
+						StartIndex: offset,
+					},
+					TextStyle: style,
+				},
+			})
+		}
+
+		text.WriteString(content)
+		offset += length
+	}
+
+	inserted := text.String()
+	if newline {
+		inserted += "\n"
+	}
+	length := TextLength(inserted)
+	paragraphEnd := start + length
+	if !newline {
+		paragraphEnd++
+	}
+
+	if inserted != "" {
+		c.requests = append(c.requests, &docs.Request{
+			InsertText: &docs.InsertTextRequest{
+				Location: &docs.Location{Index: start},
+				Text:     inserted,
+			},
+		})
+	}
+
+	c.requests = append(c.requests, &docs.Request{
+		UpdateParagraphStyle: &docs.UpdateParagraphStyleRequest{
+			Fields:         ParagraphStyleFields,
+			ParagraphStyle: copyParagraphStyle(paragraph.ParagraphStyle),
+			Range: &docs.Range{
+				EndIndex:   paragraphEnd,
+				StartIndex: start,
+			},
+		},
+	})
+
+	// Inserted text takes the style of its surroundings, so unstyled text is
+	// reset as well
+	if length > 0 {
+		c.requests = append(c.requests, &docs.Request{
+			UpdateTextStyle: &docs.UpdateTextStyleRequest{
+				Fields:    TextStyleFields,
+				Range:     &docs.Range{EndIndex: start + length, StartIndex: start},
+				TextStyle: &docs.TextStyle{},
+			},
+		})
+	}
+	c.requests = append(c.requests, styles...)
+	c.requests = append(c.requests, objects...)
+	c.index += length
+
+	if paragraph.Bullet == nil {
+		c.lastList = ""
+		return
+	}
+
+	c.tabs += int64(len(prefix))
+	preset := "BULLET_DISC_CIRCLE_SQUARE"
+	if conversion.IsOrderedList(c.source, paragraph.Bullet) {
+		preset = "NUMBERED_DECIMAL_ALPHA_ROMAN"
+	}
+
+	// Consecutive items of the same list share one bullets request
+	if c.lastList == paragraph.Bullet.ListId && len(c.lists) > 0 {
+		c.lists[len(c.lists)-1].CreateParagraphBullets.Range.EndIndex = paragraphEnd
+		return
+	}
+
+	c.lastList = paragraph.Bullet.ListId
+	c.lists = append(c.lists, &docs.Request{
+		CreateParagraphBullets: &docs.CreateParagraphBulletsRequest{
+			BulletPreset: preset,
+			Range: &docs.Range{
+				EndIndex:   paragraphEnd,
+				StartIndex: start,
+			},
+		},
+	})
+}
+
+// inlineImageRequest returns the request inserting a copy of an inline image,
+// or nil when the object is not an image
+func (c *copier) inlineImageRequest(objectID string, index int64) *docs.Request {
+	object, ok := c.source.InlineObjects[objectID]
+	if !ok || object.InlineObjectProperties == nil || object.InlineObjectProperties.EmbeddedObject == nil {
+		return nil
+	}
+
+	embedded := object.InlineObjectProperties.EmbeddedObject
+	if embedded.ImageProperties == nil {
+		return nil
+	}
+
+	uri := embedded.ImageProperties.SourceUri
+	if uri == "" {
+		uri = embedded.ImageProperties.ContentUri
+	}
+	if uri == "" {
+		return nil
+	}
+
+	return &docs.Request{
+		InsertInlineImage: &docs.InsertInlineImageRequest{
+			Location:   &docs.Location{Index: index},
+			ObjectSize: embedded.Size,
+			Uri:        uri,
+		},
+	}
+}
+
+func (c *copier) addTable(table *docs.Table) {
+	rows := table.Rows
+	columns := table.Columns
+	if rows == 0 || columns == 0 {
+		return
+	}
+
+	tableIndex := c.index
+	tableStart := &docs.Location{Index: tableIndex + 1}
+
+	c.requests = append(c.requests, &docs.Request{
+		InsertTable: &docs.InsertTableRequest{
+			Columns:  columns,
+			Location: &docs.Location{Index: tableIndex},
+			Rows:     rows,
+		},
+	})
+
+	if table.TableStyle != nil {
+		for i, properties := range table.TableStyle.TableColumnProperties {
+			if properties == nil || properties.Width == nil {
+				continue
+			}
+			c.requests = append(c.requests, &docs.Request{
+				UpdateTableColumnProperties: &docs.UpdateTableColumnPropertiesRequest{
+					ColumnIndices:         []int64{int64(i)},
+					Fields:                "width,widthType",
+					TableColumnProperties: properties,
+					TableStartLocation:    tableStart,
+				},
+			})
+		}
+	}
+
+	for r, row := range table.TableRows {
+		if row.TableRowStyle != nil && row.TableRowStyle.MinRowHeight != nil {
+			c.requests = append(c.requests, &docs.Request{
+				UpdateTableRowStyle: &docs.UpdateTableRowStyleRequest{
+					Fields:             "minRowHeight",
+					RowIndices:         []int64{int64(r)},
+					TableRowStyle:      &docs.TableRowStyle{MinRowHeight: row.TableRowStyle.MinRowHeight},
+					TableStartLocation: tableStart,
+				},
+			})
+		}
+
+		for col, cell := range row.TableCells {
+			c.addCellStyle(cell, tableStart, int64(r), int64(col))
+		}
+	}
+
+	// A newline precedes the table, then each row and each cell take one index
+	// and every empty cell holds a single newline
+	c.index = tableIndex + 4
+	for r, row := range table.TableRows {
+		for col, cell := range row.TableCells {
+			if int64(col) >= columns {
+				break
+			}
+
+			c.lastList = ""
+			var paragraphs []*docs.Paragraph
+			for _, element := range cell.Content {
+				if element.Paragraph != nil {
+					paragraphs = append(paragraphs, element.Paragraph)
+				}
+			}
+			for i, paragraph := range paragraphs {
+				c.addParagraph(paragraph, i < len(paragraphs)-1)
+			}
+
+			// Skip the cell's own newline, the cell end and the next row start
+			c.index++
+			if col < len(row.TableCells)-1 {
+				c.index++
+			} else if r < len(table.TableRows)-1 {
+				c.index += 2
+			}
+		}
+	}
+	c.lastList = ""
+}
+
+// addCellStyle copies the style and merging of a table cell
+func (c *copier) addCellStyle(cell *docs.TableCell, tableStart *docs.Location, row, column int64) {
+	style := cell.TableCellStyle
+	if style == nil {
+		return
+	}
+
+	cellRange := &docs.TableRange{
+		ColumnSpan: 1,
+		RowSpan:    1,
+		TableCellLocation: &docs.TableCellLocation{
+			ColumnIndex:        column,
+			RowIndex:           row,
+			TableStartLocation: tableStart,
+		},
+	}
+
+	var fields []string
+	for _, field := range []struct {
+		name string
+		set  bool
+	}{
+		{"backgroundColor", style.BackgroundColor != nil},
+		{"borderBottom", style.BorderBottom != nil},
+		{"borderLeft", style.BorderLeft != nil},
+		{"borderRight", style.BorderRight != nil},
+		{"borderTop", style.BorderTop != nil},
+		{"contentAlignment", style.ContentAlignment != ""},
+		{"paddingBottom", style.PaddingBottom != nil},
+		{"paddingLeft", style.PaddingLeft != nil},
+		{"paddingRight", style.PaddingRight != nil},
+		{"paddingTop", style.PaddingTop != nil},
+	} {
+		if field.set {
+			fields = append(fields, field.name)
+		}
+	}
+
+	if len(fields) > 0 {
+		cellStyle := *style
+		cellStyle.ColumnSpan = 0
+		cellStyle.RowSpan = 0
+		c.requests = append(c.requests, &docs.Request{
+			UpdateTableCellStyle: &docs.UpdateTableCellStyleRequest{
+				Fields:         strings.Join(fields, ","),
+				TableCellStyle: &cellStyle,
+				TableRange:     cellRange,
+			},
+		})
+	}
+
+	if style.RowSpan > 1 || style.ColumnSpan > 1 {
+		merged := *cellRange
+		merged.ColumnSpan = max(style.ColumnSpan, 1)
+		merged.RowSpan = max(style.RowSpan, 1)
+		c.requests = append(c.requests, &docs.Request{
+			MergeTableCells: &docs.MergeTableCellsRequest{TableRange: &merged},
+		})
+	}
+}
+
+// copyParagraphStyle returns the settable part of a paragraph style
+func copyParagraphStyle(style *docs.ParagraphStyle) *docs.ParagraphStyle {
+	copied := &docs.ParagraphStyle{}
+	if style != nil {
+		*copied = *style
+	}
+	copied.HeadingId = ""
+	copied.TabStops = nil
+	if copied.NamedStyleType == "" {
+		copied.NamedStyleType = "NORMAL_TEXT"
+	}
+	return copied
+}
+
+// replacePlaceholder swaps the placeholder at index for a one-index object
+func replacePlaceholder(index int64, insert *docs.Request) []*docs.Request {
+	return []*docs.Request{
+		{
+			DeleteContentRange: &docs.DeleteContentRangeRequest{
+				Range: &docs.Range{
+					EndIndex:   index + 1,
+					StartIndex: index,
+				},
+			},
+		},
+		insert,
+	}
+}
+
+// TextLength returns the length of text in document indices, which count
+// UTF-16 code units
+func TextLength(text string) int64 {
+	return int64(len(utf16.Encode([]rune(text))))
+}
@@ -0,0 +1,299 @@
+package document
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"google.golang.org/api/docs/v1"
+)
+
+// maxNestingLevel is the deepest list nesting level the API supports
+const maxNestingLevel = 8
+
+// BulletPresets lists every bullet preset of CreateParagraphBulletsRequest
+var BulletPresets = []string{
+	"BULLET_DISC_CIRCLE_SQUARE",
+	"BULLET_DIAMONDX_ARROW3D_SQUARE",
+	"BULLET_CHECKBOX",
+	"BULLET_ARROW_DIAMOND_DISC",
+	"BULLET_STAR_CIRCLE_SQUARE",
+	"BULLET_ARROW3D_CIRCLE_SQUARE",
+	"BULLET_LEFTTRIANGLE_DIAMOND_DISC",
+	"BULLET_DIAMONDX_HOLLOWDIAMOND_SQUARE",
+	"BULLET_DIAMOND_CIRCLE_SQUARE",
+	"NUMBERED_DECIMAL_ALPHA_ROMAN",
+	"NUMBERED_DECIMAL_ALPHA_ROMAN_PARENS",
+	"NUMBERED_DECIMAL_NESTED",
+	"NUMBERED_UPPERALPHA_ALPHA_ROMAN",
+	"NUMBERED_UPPERROMAN_UPPERALPHA_DECIMAL",
+	"NUMBERED_ZERODECIMAL_ALPHA_ROMAN",
+}
+
+// ListOptions controls how ListRequests turns paragraphs into a list
+type ListOptions struct {
+	// Continue joins the paragraphs to the closest list before them, so their
+	// numbering continues from it
+	Continue bool
+	// Level is added to the nesting level of every paragraph
+	Level  int64
+	Preset string
+	// Restart starts a new list even when the paragraph before is in a list
+	// with the same preset, which the paragraphs would otherwise join
+	Restart bool
+	// WholeLists extends the paragraphs to the whole lists they are part of
+	WholeLists bool
+}
+
+// listBlock is a run of consecutive paragraphs turned into a list together
+type listBlock struct {
+	// gap holds the paragraphs between a continued list and the target
+	// paragraphs, which are only bulleted temporarily
+	gap        map[int]bool
+	levels     map[int]int64
+	paragraphs []*TextParagraph
+	separator  bool
+}
+
+// ListRequests turn the paragraphs overlapping the ranges into lists. Items
+// already in a list keep their nesting level, to which opts.Level is added;
+// nesting is set through leading tabs, which CreateParagraphBullets removes.
+func ListRequests(doc *docs.Document, ranges []*docs.Range, opts ListOptions) ([]*docs.Request, error) {
+	if opts.Continue && opts.Restart {
+		return nil, fmt.Errorf("continuing and restarting numbering cannot be combined")
+	}
+
+	paragraphs := Paragraphs(doc.Body.Content)
+
+	selected := map[int]bool{}
+	for _, r := range ranges {
+		for i, paragraph := range paragraphs {
+			if paragraph.StartIndex < max(r.EndIndex, r.StartIndex+1) && paragraph.EndIndex > r.StartIndex {
+				selected[i] = true
+			}
+		}
+	}
+
+	if opts.WholeLists {
+		for i := range selected {
+			listID := bulletListID(paragraphs[i])
+			if listID == "" {
+				continue
+			}
+			for j, paragraph := range paragraphs {
+				if bulletListID(paragraph) == listID {
+					selected[j] = true
+				}
+			}
+		}
+	}
+
+	if len(selected) == 0 {
+		return nil, fmt.Errorf("no paragraphs in range")
+	}
+
+	indices := make([]int, 0, len(selected))
+	for i := range selected {
+		indices = append(indices, i)
+	}
+	sort.Ints(indices)
+
+	// Adjacent paragraphs form one block; tables and cell boundaries separate them
+	var groups [][]int
+	for _, i := range indices {
+		if n := len(groups); n > 0 {
+			last := groups[n-1][len(groups[n-1])-1]
+			if last == i-1 && paragraphs[last].EndIndex == paragraphs[i].StartIndex {
+				groups[n-1] = append(groups[n-1], i)
+				continue
+			}
+		}
+		groups = append(groups, []int{i})
+	}
+
+	if (opts.Continue || opts.Restart) && len(groups) > 1 {
+		return nil, fmt.Errorf("continuing or restarting numbering applies to a single run of paragraphs")
+	}
+
+	var blocks []*listBlock
+	for _, group := range groups {
+		block := &listBlock{gap: map[int]bool{}, levels: map[int]int64{}}
+
+		first := group[0]
+		var gap []int
+		if opts.Continue {
+			prior := first - 1
+			for prior >= 0 && bulletListID(paragraphs[prior]) == "" {
+				if paragraphs[prior].EndIndex != paragraphs[prior+1].StartIndex {
+					return nil, fmt.Errorf("cannot continue numbering across a table")
+				}
+				gap = append(gap, prior)
+				prior--
+			}
+			if prior < 0 || paragraphs[prior].EndIndex != paragraphs[prior+1].StartIndex {
+				return nil, fmt.Errorf("no list before the paragraphs to continue")
+			}
+
+			listID := bulletListID(paragraphs[prior])
+			for prior > 0 && bulletListID(paragraphs[prior-1]) == listID && paragraphs[prior-1].EndIndex == paragraphs[prior].StartIndex {
+				prior--
+			}
+			first = prior
+		}
+
+		for _, i := range gap {
+			block.gap[i-first] = true
+		}
+
+		for i := first; i <= group[len(group)-1]; i++ {
+			paragraph := paragraphs[i]
+			block.paragraphs = append(block.paragraphs, paragraph)
+
+			level := int64(0)
+			if paragraph.Paragraph.Bullet != nil {
+				level = paragraph.Paragraph.Bullet.NestingLevel
+			}
+			if selected[i] {
+				level += opts.Level
+			}
+			if level > maxNestingLevel {
+				return nil, fmt.Errorf("nesting level %d is too deep (maximum %d)", level, maxNestingLevel)
+			}
+			block.levels[len(block.paragraphs)-1] = level
+		}
+
+		block.separator = opts.Restart && first > 0 && bulletListID(paragraphs[first-1]) != ""
+		blocks = append(blocks, block)
+	}
+
+	// Blocks are built from the last one so earlier indices stay valid
+	bodyEnd := doc.Body.Content[len(doc.Body.Content)-1].EndIndex
+
+	var requests []*docs.Request
+	for i := len(blocks) - 1; i >= 0; i-- {
+		requests = append(requests, blocks[i].requests(opts.Preset, bodyEnd)...)
+	}
+
+	return requests, nil
+}
+
+// requests builds a block's list: existing bullets are removed, leading tabs
+// inserted for nesting, and bullets created over the whole block. Gap
+// paragraphs then lose their temporary bullets, and a separator paragraph
+// keeping the list from joining the one before is removed.
+func (b *listBlock) requests(preset string, bodyEnd int64) []*docs.Request {
+	first := b.paragraphs[0]
+	last := b.paragraphs[len(b.paragraphs)-1]
+	start := first.StartIndex
+	end := last.EndIndex
+
+	// Ranges stop before the final newline of the document
+	if end == bodyEnd && end-1 > last.StartIndex {
+		end--
+	}
+
+	var requests []*docs.Request
+
+	bulleted := false
+	for _, paragraph := range b.paragraphs {
+		bulleted = bulleted || paragraph.Paragraph.Bullet != nil
+	}
+	if bulleted {
+		// Deleting bullets indents paragraphs to preserve their look, which
+		// would add to the indentation of the new list
+		requests = append(requests,
+			&docs.Request{
+				DeleteParagraphBullets: &docs.DeleteParagraphBulletsRequest{
+					Range: &docs.Range{EndIndex: end, StartIndex: start},
+				},
+			},
+			&docs.Request{
+				UpdateParagraphStyle: &docs.UpdateParagraphStyleRequest{
+					Fields:         "indentFirstLine,indentStart",
+					ParagraphStyle: &docs.ParagraphStyle{},
+					Range:          &docs.Range{EndIndex: end, StartIndex: start},
+				},
+			},
+		)
+	}
+
+	tabs := int64(0)
+	for i := len(b.paragraphs) - 1; i >= 0; i-- {
+		level := b.levels[i]
+		if level == 0 || b.gap[i] {
+			continue
+		}
+
+		requests = append(requests, &docs.Request{
+			InsertText: &docs.InsertTextRequest{
+				Location: &docs.Location{Index: b.paragraphs[i].StartIndex},
+				Text:     strings.Repeat("\t", int(level)),
+			},
+		})
+		tabs += level
+	}
+
+	offset := int64(0)
+	if b.separator {
+		requests = append(requests, &docs.Request{
+			InsertText: &docs.InsertTextRequest{
+				Location: &docs.Location{Index: start},
+				Text:     "\n",
+			},
+		})
+		offset = 1
+	}
+
+	requests = append(requests, &docs.Request{
+		CreateParagraphBullets: &docs.CreateParagraphBulletsRequest{
+			BulletPreset: preset,
+			Range: &docs.Range{
+				EndIndex:   end + tabs + offset,
+				StartIndex: start + offset,
+			},
+		},
+	})
+
+	// The tabs are gone once bullets are created, so indices are back to the original ones
+	for i, paragraph := range b.paragraphs {
+		if !b.gap[i] {
+			continue
+		}
+
+		style := &docs.ParagraphStyle{}
+		if paragraph.Paragraph.ParagraphStyle != nil {
+			style.IndentFirstLine = paragraph.Paragraph.ParagraphStyle.IndentFirstLine
+			style.IndentStart = paragraph.Paragraph.ParagraphStyle.IndentStart
+		}
+
+		r := &docs.Range{EndIndex: paragraph.EndIndex, StartIndex: paragraph.StartIndex}
+		requests = append(requests,
+			&docs.Request{DeleteParagraphBullets: &docs.DeleteParagraphBulletsRequest{Range: r}},
+			&docs.Request{
+				UpdateParagraphStyle: &docs.UpdateParagraphStyleRequest{
+					Fields:         "indentFirstLine,indentStart",
+					ParagraphStyle: style,
+					Range:          r,
+				},
+			},
+		)
+	}
+
+	if b.separator {
+		requests = append(requests, &docs.Request{
+			DeleteContentRange: &docs.DeleteContentRangeRequest{
+				Range: &docs.Range{EndIndex: start + 1, StartIndex: start},
+			},
+		})
+	}
+
+	return requests
+}
+
+// bulletListID returns the ID of the list a paragraph is in, if any
+func bulletListID(paragraph *TextParagraph) string {
+	if paragraph.Paragraph.Bullet == nil {
+		return ""
+	}
+	return paragraph.Paragraph.Bullet.ListId
+}
@@ -0,0 +1,295 @@
+package document
+
+import (
+	"encoding/csv"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"google.golang.org/api/docs/v1"
+)
+
+// TextParagraph is the text of a paragraph along with the document index of
+// every byte of it, so text matches can be turned into document ranges
+type TextParagraph struct {
+	EndIndex int64
+	InTable  bool
+	// Offsets holds the document index of each byte of Text, plus the index
+	// of the paragraph's newline
+	Offsets    []int64
+	Paragraph  *docs.Paragraph
+	StartIndex int64
+	// Text is the paragraph text without its newline. Chips, images and other
+	// non-text elements appear as U+FFFC.
+	Text string
+}
+
+// TextMatch is a match of a pattern in a paragraph, as byte offsets into its text
+type TextMatch struct {
+	// Groups holds the start and end offsets of the match and its capture
+	// groups, as returned by regexp.FindAllStringSubmatchIndex
+	Groups    []int
+	Paragraph *TextParagraph
+}
+
+// RangeSelector selects ranges of the document body. Each set field narrows
+// the selection: a section, then a paragraph in it, then text matches.
+type RangeSelector struct {
+	Match string
+	// Occurrence is a 1-based match number or "all"; the first match is selected by default
+	Occurrence string
+	// Paragraph is a 1-based paragraph number in document order, counting the
+	// paragraphs of table cells
+	Paragraph int
+	Regex     string
+	Section   string
+}
+
+// Paragraphs returns the paragraphs of a body, header, footer or footnote in
+// document order, including those of table cells
+func Paragraphs(content []*docs.StructuralElement) []*TextParagraph {
+	return collectParagraphs(content, false)
+}
+
+func collectParagraphs(content []*docs.StructuralElement, inTable bool) []*TextParagraph {
+	var paragraphs []*TextParagraph
+
+	for _, element := range content {
+		switch {
+		case element.Paragraph != nil:
+			paragraph := newTextParagraph(element)
+			paragraph.InTable = inTable
+			paragraphs = append(paragraphs, paragraph)
+		case element.Table != nil:
+			for _, row := range element.Table.TableRows {
+				for _, cell := range row.TableCells {
+					paragraphs = append(paragraphs, collectParagraphs(cell.Content, true)...)
+				}
+			}
+		}
+	}
+
+	return paragraphs
+}
+
+func newTextParagraph(element *docs.StructuralElement) *TextParagraph {
+	p := &TextParagraph{
+		EndIndex:   element.EndIndex,
+		Paragraph:  element.Paragraph,
+		StartIndex: element.StartIndex,
+	}
+
+	var text strings.Builder
+	for _, e := range element.Paragraph.Elements {
+		if e.TextRun == nil {
+			text.WriteString(objectPlaceholder)
+			for range len(objectPlaceholder) {
+				p.Offsets = append(p.Offsets, e.StartIndex)
+			}
+			continue
+		}
+
+		index := e.StartIndex
+		for _, r := range strings.TrimSuffix(e.TextRun.Content, "\n") {
+			text.WriteRune(r)
+			for range utf8.RuneLen(r) {
+				p.Offsets = append(p.Offsets, index)
+			}
+			index += int64(len(utf16.Encode([]rune{r})))
+		}
+	}
+
+	p.Text = text.String()
+	p.Offsets = append(p.Offsets, element.EndIndex-1)
+	return p
+}
+
+// Range returns the document range of the text between two byte offsets
+func (p *TextParagraph) Range(start, end int) *docs.Range {
+	return &docs.Range{
+		EndIndex:   p.Offsets[end],
+		StartIndex: p.Offsets[start],
+	}
+}
+
+// Context returns up to width characters of text before and after the text
+// between two byte offsets
+func (p *TextParagraph) Context(start, end, width int) (string, string) {
+	before := start
+	for n := 0; n < width && before > 0; n++ {
+		_, size := utf8.DecodeLastRuneInString(p.Text[:before])
+		before -= size
+	}
+
+	after := end
+	for n := 0; n < width && after < len(p.Text); n++ {
+		_, size := utf8.DecodeRuneInString(p.Text[after:])
+		after += size
+	}
+
+	return p.Text[before:start], p.Text[end:after]
+}
+
+// FindMatches returns the matches of a pattern in the paragraphs, in document order
+func FindMatches(paragraphs []*TextParagraph, pattern *regexp.Regexp) []*TextMatch {
+	var matches []*TextMatch
+	for _, paragraph := range paragraphs {
+		for _, groups := range pattern.FindAllStringSubmatchIndex(paragraph.Text, -1) {
+			if groups[0] == groups[1] {
+				continue
+			}
+			matches = append(matches, &TextMatch{Groups: groups, Paragraph: paragraph})
+		}
+	}
+	return matches
+}
+
+// Range returns the document range of the whole match
+func (m *TextMatch) Range() *docs.Range {
+	return m.Paragraph.Range(m.Groups[0], m.Groups[1])
+}
+
+// IsEmpty reports whether the selector selects nothing
+func (s RangeSelector) IsEmpty() bool {
+	return s.Match == "" && s.Regex == "" && s.Section == "" && s.Paragraph == 0
+}
+
+// ResolveRanges resolves a selector to ranges of the document body, in document order
+func ResolveRanges(doc *docs.Document, selector RangeSelector) ([]*docs.Range, error) {
+	if selector.IsEmpty() {
+		return nil, fmt.Errorf("empty range selector")
+	}
+	if selector.Match != "" && selector.Regex != "" {
+		return nil, fmt.Errorf("--match and --regex cannot be combined")
+	}
+
+	end := doc.Body.Content[len(doc.Body.Content)-1].EndIndex
+
+	// The final newline of the document cannot be deleted, so ranges stop before it
+	scope := &docs.Range{StartIndex: 1, EndIndex: end - 1}
+	if selector.Section != "" {
+		section, err := FindSection(doc, selector.Section)
+		if err != nil {
+			return nil, err
+		}
+		scope = &docs.Range{StartIndex: section.StartIndex, EndIndex: min(section.BodyEndIndex, end-1)}
+	}
+
+	var paragraphs []*TextParagraph
+	for _, paragraph := range Paragraphs(doc.Body.Content) {
+		if paragraph.StartIndex >= scope.StartIndex && paragraph.StartIndex < scope.EndIndex {
+			paragraphs = append(paragraphs, paragraph)
+		}
+	}
+
+	if selector.Paragraph != 0 {
+		if selector.Paragraph < 0 || selector.Paragraph > len(paragraphs) {
+			return nil, fmt.Errorf("paragraph %d not found: only %d paragraphs in range", selector.Paragraph, len(paragraphs))
+		}
+
+		paragraph := paragraphs[selector.Paragraph-1]
+		paragraphs = []*TextParagraph{paragraph}
+		scope = &docs.Range{StartIndex: paragraph.StartIndex, EndIndex: min(paragraph.EndIndex, end-1)}
+	}
+
+	if selector.Match == "" && selector.Regex == "" {
+		return []*docs.Range{scope}, nil
+	}
+
+	pattern, err := selectorPattern(selector)
+	if err != nil {
+		return nil, err
+	}
+
+	matches := FindMatches(paragraphs, pattern)
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("no text matches %q", selector.Match+selector.Regex)
+	}
+
+	occurrence := strings.ToLower(strings.TrimSpace(selector.Occurrence))
+	if occurrence == "" {
+		occurrence = "1"
+	}
+
+	if occurrence == "all" {
+		ranges := make([]*docs.Range, len(matches))
+		for i, match := range matches {
+			ranges[i] = match.Range()
+		}
+		return ranges, nil
+	}
+
+	n, err := strconv.Atoi(occurrence)
+	if err != nil || n < 1 {
+		return nil, fmt.Errorf("invalid occurrence: %s (must be a positive number or all)", selector.Occurrence)
+	}
+	if n > len(matches) {
+		return nil, fmt.Errorf("occurrence %d not found: only %d matches", n, len(matches))
+	}
+
+	return []*docs.Range{matches[n-1].Range()}, nil
+}
+
+// ParseRangeSelector parses a selector written as comma-separated key=value
+// pairs, e.g. section=Intro,paragraph=2 or match=TODO,occurrence=all. The keys
+// are section, paragraph, match, regex and occurrence, and a value without a
+// key is a section. Values containing commas are quoted as in CSV.
+func ParseRangeSelector(text string) (RangeSelector, error) {
+	selector := RangeSelector{}
+	if strings.TrimSpace(text) == "" {
+		return selector, fmt.Errorf("empty selector")
+	}
+
+	reader := csv.NewReader(strings.NewReader(text))
+	reader.LazyQuotes = true
+	parts, err := reader.Read()
+	if err != nil {
+		return selector, fmt.Errorf("invalid selector %q: %w", text, err)
+	}
+
+	for _, part := range parts {
+		key, value, found := strings.Cut(part, "=")
+		if !found {
+			key, value = "section", part
+		}
+
+		switch strings.ToLower(strings.TrimSpace(key)) {
+		case "match":
+			selector.Match = value
+		case "occurrence":
+			selector.Occurrence = value
+		case "paragraph":
+			selector.Paragraph, err = strconv.Atoi(strings.TrimSpace(value))
+			if err != nil || selector.Paragraph < 1 {
+				return selector, fmt.Errorf("invalid paragraph in selector %q: %s", text, value)
+			}
+		case "regex":
+			selector.Regex = value
+		case "section":
+			selector.Section = strings.TrimSpace(value)
+		default:
+			return selector, fmt.Errorf("invalid selector key %q (must be section, paragraph, match, regex or occurrence)", key)
+		}
+	}
+
+	if selector.IsEmpty() {
+		return selector, fmt.Errorf("invalid selector %q: no section, paragraph, match or regex", text)
+	}
+
+	return selector, nil
+}
+
+func selectorPattern(selector RangeSelector) (*regexp.Regexp, error) {
+	if selector.Match != "" {
+		return regexp.MustCompile(regexp.QuoteMeta(selector.Match)), nil
+	}
+
+	pattern, err := regexp.Compile(selector.Regex)
+	if err != nil {
+		return nil, fmt.Errorf("invalid regex: %w", err)
+	}
+	return pattern, nil
+}
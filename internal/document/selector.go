@@ -0,0 +1,264 @@
+package document
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"google.golang.org/api/docs/v1"
+)
+
+const (
+	// maxSuggestions limits the candidates listed when a selector does not match
+	maxSuggestions = 5
+
+	pathSeparator = " > "
+)
+
+var ordinalRegex = regexp.MustCompile(`^(.*?)\s*#(\d+)$`)
+
+// FindSection finds a section by selector. The returned section includes its
+// subsections in Children. A selector is one of:
+//
+//	Overview               heading title, case-insensitive
+//	Design > API > Errors  title path; ancestors may be skipped, and a > in
+//	                       a title is written \>
+//	Overview#2             the second matching section in document order
+//	h.abc123               heading ID
+//	/^API (v1|v2)$/        regular expression, usable in any path component
+//	~Overveiw              closest fuzzy match
+//
+// When nothing matches, or a selector matches several sections, the error
+// lists the best candidates.
+func FindSection(doc *docs.Document, selector string) (*Section, error) {
+	return selectSection(flattenSections(GetSectionTree(doc)), selector)
+}
+
+func flattenSections(tree []*Section) []*Section {
+	var sections []*Section
+	for _, section := range tree {
+		sections = append(sections, section)
+		sections = append(sections, flattenSections(section.Children)...)
+	}
+	return sections
+}
+
+func selectSection(sections []*Section, selector string) (*Section, error) {
+	selector = strings.TrimSpace(selector)
+	if selector == "" {
+		return nil, fmt.Errorf("empty section selector")
+	}
+
+	if strings.HasPrefix(selector, "h.") {
+		for _, section := range sections {
+			if section.HeadingID == selector {
+				return section, nil
+			}
+		}
+	}
+
+	if strings.HasPrefix(selector, "~") {
+		query := strings.TrimSpace(selector[1:])
+		ranked := rankSections(sections, query)
+		if len(ranked) == 0 {
+			return nil, fmt.Errorf("section not found: %q", query)
+		}
+		return ranked[0], nil
+	}
+
+	matches, err := matchSections(sections, selector)
+	if err != nil {
+		return nil, err
+	}
+
+	ordinal := 0
+	if len(matches) == 0 {
+		if m := ordinalRegex.FindStringSubmatch(selector); m != nil {
+			ordinal, _ = strconv.Atoi(m[2])
+			if ordinal < 1 {
+				return nil, fmt.Errorf("invalid section ordinal in %q: must be 1 or greater", selector)
+			}
+
+			selector = m[1]
+			matches, err = matchSections(sections, selector)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	switch {
+	case len(matches) == 0:
+		return nil, notFoundError(sections, selector)
+	case ordinal > len(matches):
+		return nil, fmt.Errorf("section %q not found: only %d matching sections (%s)", fmt.Sprintf("%s#%d", selector, ordinal), len(matches), quotePaths(matches))
+	case ordinal > 0:
+		return matches[ordinal-1], nil
+	case len(matches) > 1:
+		return nil, fmt.Errorf("section %q is ambiguous, it matches %s (use a path like %q or an ordinal like %q)",
+			selector, quotePaths(matches), matches[1].Path(), selector+"#2")
+	}
+
+	return matches[0], nil
+}
+
+// matchSections returns the sections whose path matches every component of the
+// selector, the last component matching the section title itself
+func matchSections(sections []*Section, selector string) ([]*Section, error) {
+	var matchers []func(string) bool
+	for _, component := range splitSelector(selector) {
+		matcher, err := componentMatcher(strings.TrimSpace(component))
+		if err != nil {
+			return nil, err
+		}
+		matchers = append(matchers, matcher)
+	}
+
+	var matches []*Section
+	for _, section := range sections {
+		if !matchers[len(matchers)-1](section.Title) {
+			continue
+		}
+
+		// Match the remaining components against the ancestors in order
+		remaining := matchers[:len(matchers)-1]
+		for i := len(section.ParentPath) - 1; i >= 0 && len(remaining) > 0; i-- {
+			if remaining[len(remaining)-1](section.ParentPath[i]) {
+				remaining = remaining[:len(remaining)-1]
+			}
+		}
+		if len(remaining) == 0 {
+			matches = append(matches, section)
+		}
+	}
+
+	return matches, nil
+}
+
+// splitSelector splits a selector into its path components at each > not
+// escaped with a backslash, unescaping the others
+func splitSelector(selector string) []string {
+	var components []string
+	var component strings.Builder
+
+	for i := 0; i < len(selector); i++ {
+		switch {
+		case selector[i] == '\\' && i+1 < len(selector) && selector[i+1] == '>':
+			component.WriteByte('>')
+			i++
+		case selector[i] == '>':
+			components = append(components, component.String())
+			component.Reset()
+		default:
+			component.WriteByte(selector[i])
+		}
+	}
+
+	return append(components, component.String())
+}
+
+// componentMatcher matches a title against a path component, either as a
+// case-insensitive title or as a /regular expression/
+func componentMatcher(component string) (func(string) bool, error) {
+	if len(component) > 1 && strings.HasPrefix(component, "/") && strings.HasSuffix(component, "/") {
+		re, err := regexp.Compile("(?i)" + component[1:len(component)-1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid section pattern %s: %w", component, err)
+		}
+		return re.MatchString, nil
+	}
+
+	return func(title string) bool {
+		return strings.EqualFold(strings.TrimSpace(title), component)
+	}, nil
+}
+
+func notFoundError(sections []*Section, selector string) error {
+	components := splitSelector(selector)
+	query := strings.Trim(strings.TrimSpace(components[len(components)-1]), "/")
+
+	ranked := rankSections(sections, query)
+	if len(ranked) == 0 {
+		return fmt.Errorf("section not found: %q", selector)
+	}
+	if len(ranked) > maxSuggestions {
+		ranked = ranked[:maxSuggestions]
+	}
+
+	return fmt.Errorf("section not found: %q (did you mean %s?)", selector, quotePaths(ranked))
+}
+
+// rankSections orders the sections resembling the query by similarity,
+// dropping those that are not similar at all
+func rankSections(sections []*Section, query string) []*Section {
+	type candidate struct {
+		contains bool
+		distance int
+		section  *Section
+	}
+
+	query = strings.ToLower(query)
+
+	var candidates []candidate
+	for _, section := range sections {
+		title := strings.ToLower(strings.TrimSpace(section.Title))
+		c := candidate{
+			contains: query != "" && (strings.Contains(title, query) || strings.Contains(query, title)),
+			distance: levenshtein(query, title),
+			section:  section,
+		}
+
+		longest := max(len([]rune(query)), len([]rune(title)))
+		if c.contains || c.distance <= longest/2 {
+			candidates = append(candidates, c)
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].contains != candidates[j].contains {
+			return candidates[i].contains
+		}
+		return candidates[i].distance < candidates[j].distance
+	})
+
+	ranked := make([]*Section, len(candidates))
+	for i, c := range candidates {
+		ranked[i] = c.section
+	}
+	return ranked
+}
+
+// levenshtein returns the edit distance between two strings
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	previous := make([]int, len(rb)+1)
+	current := make([]int, len(rb)+1)
+
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		current[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+
+	return previous[len(rb)]
+}
+
+func quotePaths(sections []*Section) string {
+	quoted := make([]string, len(sections))
+	for i, section := range sections {
+		quoted[i] = strconv.Quote(section.Path())
+	}
+	return strings.Join(quoted, ", ")
+}
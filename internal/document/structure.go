@@ -8,15 +8,23 @@ import (
 	"google.golang.org/api/docs/v1"
 )
 
-// Section represents a document section/heading
+// Section represents a document section/heading. StartIndex and EndIndex cover
+// the heading paragraph; the body runs from BodyStartIndex up to the next heading
+// of the same or higher level (or the end of the document) at BodyEndIndex.
 type Section struct {
-	EndIndex   int64  `json:"endIndex"`
-	Level      int    `json:"level"`
-	StartIndex int64  `json:"startIndex"`
-	Title      string `json:"title"`
+	BodyEndIndex   int64      `json:"bodyEndIndex"`
+	BodyStartIndex int64      `json:"bodyStartIndex"`
+	Children       []*Section `json:"children,omitempty"`
+	EndIndex       int64      `json:"endIndex"`
+	HeadingID      string     `json:"headingId,omitempty"`
+	Level          int        `json:"level"`
+	ParentPath     []string   `json:"parentPath"`
+	StartIndex     int64      `json:"startIndex"`
+	Title          string     `json:"title"`
 }
 
-// GetStructure extracts the structure of a document
+// GetStructure extracts the structure of a document as a flat list of sections
+// in document order
 func GetStructure(doc *docs.Document) []Section {
 	var sections []Section
 
@@ -36,6 +44,7 @@ func GetStructure(doc *docs.Document) []Section {
 
 					sections = append(sections, Section{
 						EndIndex:   endIndex,
+						HeadingID:  paragraph.ParagraphStyle.HeadingId,
 						Level:      level,
 						StartIndex: startIndex,
 						Title:      text,
@@ -45,18 +54,86 @@ func GetStructure(doc *docs.Document) []Section {
 		}
 	}
 
+	documentEnd := int64(0)
+	if content := doc.Body.Content; len(content) > 0 {
+		documentEnd = content[len(content)-1].EndIndex
+	}
+
+	var parents []int
+	for i := range sections {
+		section := &sections[i]
+
+		for len(parents) > 0 && sections[parents[len(parents)-1]].Level >= section.Level {
+			parents = parents[:len(parents)-1]
+		}
+
+		section.ParentPath = []string{}
+		for _, parent := range parents {
+			section.ParentPath = append(section.ParentPath, sections[parent].Title)
+		}
+		parents = append(parents, i)
+
+		section.BodyStartIndex = section.EndIndex
+		section.BodyEndIndex = documentEnd
+		for _, next := range sections[i+1:] {
+			if next.Level <= section.Level {
+				section.BodyEndIndex = next.StartIndex
+				break
+			}
+		}
+	}
+
 	return sections
 }
 
-// FindSection finds a section by name
-func FindSection(doc *docs.Document, sectionName string) *Section {
+// GetSectionTree returns the top-level sections of a document with their
+// subsections nested in Children
+func GetSectionTree(doc *docs.Document) []*Section {
 	sections := GetStructure(doc)
 
-	for _, section := range sections {
-		if strings.EqualFold(section.Title, sectionName) {
-			return &section
+	var roots []*Section
+	var stack []*Section
+
+	for i := range sections {
+		section := &sections[i]
+
+		for len(stack) > 0 && stack[len(stack)-1].Level >= section.Level {
+			stack = stack[:len(stack)-1]
+		}
+
+		if len(stack) == 0 {
+			roots = append(roots, section)
+		} else {
+			parent := stack[len(stack)-1]
+			parent.Children = append(parent.Children, section)
 		}
+		stack = append(stack, section)
 	}
 
-	return nil
+	return roots
+}
+
+// SectionAt returns the innermost section of a flat section list containing
+// index, or nil before the first heading
+func SectionAt(sections []Section, index int64) *Section {
+	var found *Section
+	for i := range sections {
+		if sections[i].StartIndex > index {
+			break
+		}
+		if index < sections[i].BodyEndIndex {
+			found = &sections[i]
+		}
+	}
+	return found
+}
+
+// Path returns the full path of the section, e.g. "Design > API > Errors".
+// A > in a title is escaped, so the path can be used as a selector.
+func (s *Section) Path() string {
+	titles := make([]string, 0, len(s.ParentPath)+1)
+	for _, title := range append(append([]string{}, s.ParentPath...), s.Title) {
+		titles = append(titles, strings.ReplaceAll(title, ">", `\>`))
+	}
+	return strings.Join(titles, pathSeparator)
 }
@@ -0,0 +1,248 @@
+package document
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"google.golang.org/api/docs/v1"
+	"gopkg.in/yaml.v3"
+)
+
+// MarshalNamedStyles encodes named styles as YAML, with the API's field names
+func MarshalNamedStyles(styles *docs.NamedStyles) ([]byte, error) {
+	// Going through JSON keeps the API's camelCase names and omits unset fields
+	data, err := json.Marshal(styles)
+	if err != nil {
+		return nil, err
+	}
+
+	var value any
+	if err := json.Unmarshal(data, &value); err != nil {
+		return nil, err
+	}
+
+	var buffer bytes.Buffer
+	encoder := yaml.NewEncoder(&buffer)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(value); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+
+	return buffer.Bytes(), nil
+}
+
+// ParseNamedStyles decodes named styles written by MarshalNamedStyles. JSON is
+// accepted too, being a subset of YAML.
+func ParseNamedStyles(data []byte) (*docs.NamedStyles, error) {
+	var value any
+	if err := yaml.Unmarshal(data, &value); err != nil {
+		return nil, fmt.Errorf("error parsing styles: %w", err)
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing styles: %w", err)
+	}
+
+	styles := &docs.NamedStyles{}
+	if err := json.Unmarshal(data, styles); err != nil {
+		return nil, fmt.Errorf("error parsing styles: %w", err)
+	}
+
+	for i, style := range styles.Styles {
+		if style == nil || style.NamedStyleType == "" {
+			return nil, fmt.Errorf("error parsing styles: style %d has no namedStyleType", i+1)
+		}
+	}
+
+	return styles, nil
+}
+
+// ThemeRequests restyle the body paragraphs of each named style type of a
+// theme. Named styles themselves cannot be updated through the API, so the
+// theme is applied to the paragraphs instead. Only the fields the theme
+// changes from the document's named styles are applied, keeping inline
+// formatting, unless all is set.
+func ThemeRequests(doc *docs.Document, theme *docs.NamedStyles, all bool) []*docs.Request {
+	current := map[string]*docs.NamedStyle{}
+	if doc.NamedStyles != nil {
+		for _, style := range doc.NamedStyles.Styles {
+			current[style.NamedStyleType] = style
+		}
+	}
+
+	type update struct {
+		paragraphFields []string
+		style           *docs.NamedStyle
+		textFields      []string
+	}
+
+	updates := map[string]update{}
+	for _, style := range theme.Styles {
+		var currentParagraphStyle *docs.ParagraphStyle
+		var currentTextStyle *docs.TextStyle
+		if existing := current[style.NamedStyleType]; existing != nil && !all {
+			currentParagraphStyle = existing.ParagraphStyle
+			currentTextStyle = existing.TextStyle
+		}
+
+		u := update{
+			paragraphFields: changedFields(style.ParagraphStyle, currentParagraphStyle, ParagraphStyleFields),
+			style:           style,
+			textFields:      changedFields(style.TextStyle, currentTextStyle, TextStyleFields),
+		}
+		if len(u.paragraphFields) > 0 || len(u.textFields) > 0 {
+			updates[style.NamedStyleType] = u
+		}
+	}
+
+	var requests []*docs.Request
+	for _, paragraph := range Paragraphs(doc.Body.Content) {
+		if paragraph.Paragraph.ParagraphStyle == nil {
+			continue
+		}
+
+		u, ok := updates[paragraph.Paragraph.ParagraphStyle.NamedStyleType]
+		if !ok {
+			continue
+		}
+
+		if len(u.paragraphFields) > 0 {
+			requests = append(requests, &docs.Request{
+				UpdateParagraphStyle: &docs.UpdateParagraphStyleRequest{
+					Fields:         strings.Join(u.paragraphFields, ","),
+					ParagraphStyle: u.style.ParagraphStyle,
+					Range: &docs.Range{
+						EndIndex:   paragraph.EndIndex,
+						StartIndex: paragraph.StartIndex,
+					},
+				},
+			})
+		}
+
+		if len(u.textFields) > 0 && paragraph.EndIndex-1 > paragraph.StartIndex {
+			requests = append(requests, &docs.Request{
+				UpdateTextStyle: &docs.UpdateTextStyleRequest{
+					Fields: strings.Join(u.textFields, ","),
+					Range: &docs.Range{
+						EndIndex:   paragraph.EndIndex - 1,
+						StartIndex: paragraph.StartIndex,
+					},
+					TextStyle: u.style.TextStyle,
+				},
+			})
+		}
+	}
+
+	return requests
+}
+
+// StyleAt returns the text style of the text at an index of a body, header,
+// footer or footnote, and the paragraph style of its paragraph
+func StyleAt(content []*docs.StructuralElement, index int64) (*docs.TextStyle, *docs.ParagraphStyle, error) {
+	for _, paragraph := range Paragraphs(content) {
+		if index < paragraph.StartIndex || index >= paragraph.EndIndex {
+			continue
+		}
+
+		// Chips and other objects have no text style of their own, so the
+		// first text run of the paragraph stands in for them
+		var textStyle *docs.TextStyle
+		for _, element := range paragraph.Paragraph.Elements {
+			if element.TextRun == nil {
+				continue
+			}
+			if textStyle == nil || element.StartIndex <= index && index < element.EndIndex {
+				textStyle = element.TextRun.TextStyle
+			}
+			if element.StartIndex <= index && index < element.EndIndex {
+				break
+			}
+		}
+		if textStyle == nil {
+			textStyle = &docs.TextStyle{}
+		}
+
+		return textStyle, paragraph.Paragraph.ParagraphStyle, nil
+	}
+
+	return nil, nil, fmt.Errorf("no paragraph at index %d", index)
+}
+
+// CopyStyleRequests apply a copied text style and paragraph style to ranges,
+// resetting the fields the copied styles leave unset. Links are not copied.
+// Either style may be nil to leave it unchanged.
+func CopyStyleRequests(textStyle *docs.TextStyle, paragraphStyle *docs.ParagraphStyle, ranges []*docs.Range) []*docs.Request {
+	var requests []*docs.Request
+
+	var textFields []string
+	for _, field := range strings.Split(TextStyleFields, ",") {
+		if field != "link" {
+			textFields = append(textFields, field)
+		}
+	}
+
+	for _, r := range ranges {
+		if paragraphStyle != nil {
+			requests = append(requests, &docs.Request{
+				UpdateParagraphStyle: &docs.UpdateParagraphStyleRequest{
+					Fields:         ParagraphStyleFields,
+					ParagraphStyle: copyParagraphStyle(paragraphStyle),
+					Range:          r,
+				},
+			})
+		}
+
+		if textStyle != nil && r.EndIndex > r.StartIndex {
+			copied := *textStyle
+			copied.Link = nil
+			requests = append(requests, &docs.Request{
+				UpdateTextStyle: &docs.UpdateTextStyleRequest{
+					Fields:    strings.Join(textFields, ","),
+					Range:     r,
+					TextStyle: &copied,
+				},
+			})
+		}
+	}
+
+	return requests
+}
+
+// changedFields returns the settable fields a style sets to a different value
+// than the current style. The named style type is never changed.
+func changedFields(style, current any, settable string) []string {
+	styleFields := jsonFields(style)
+	currentFields := jsonFields(current)
+
+	var fields []string
+	for _, field := range strings.Split(settable, ",") {
+		value, ok := styleFields[field]
+		if !ok || field == "namedStyleType" {
+			continue
+		}
+		if string(value) != string(currentFields[field]) {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+// jsonFields returns the JSON encoding of each field set in a style
+func jsonFields(style any) map[string]json.RawMessage {
+	fields := map[string]json.RawMessage{}
+
+	data, err := json.Marshal(style)
+	if err != nil {
+		return fields
+	}
+
+	// A nil style encodes as null, which leaves the map empty
+	_ = json.Unmarshal(data, &fields)
+	return fields
+}
@@ -0,0 +1,44 @@
+package document
+
+import (
+	"fmt"
+	"strings"
+
+	"google.golang.org/api/docs/v1"
+)
+
+// TabDocument returns a view of a document fetched with its tabs content in
+// which the body, lists and other content are those of a tab, found by ID or
+// title. Ranges and locations in the view need the returned tab ID.
+func TabDocument(doc *docs.Document, tab string) (*docs.Document, string, error) {
+	found := findTab(doc.Tabs, tab)
+	if found == nil || found.DocumentTab == nil {
+		return nil, "", fmt.Errorf("tab not found: %s", tab)
+	}
+
+	content := found.DocumentTab
+	return &docs.Document{
+		Body:          content.Body,
+		DocumentId:    doc.DocumentId,
+		DocumentStyle: content.DocumentStyle,
+		Footers:       content.Footers,
+		Footnotes:     content.Footnotes,
+		Headers:       content.Headers,
+		InlineObjects: content.InlineObjects,
+		Lists:         content.Lists,
+		NamedStyles:   content.NamedStyles,
+		Title:         doc.Title,
+	}, found.TabProperties.TabId, nil
+}
+
+func findTab(tabs []*docs.Tab, tab string) *docs.Tab {
+	for _, t := range tabs {
+		if t.TabProperties != nil && (t.TabProperties.TabId == tab || strings.EqualFold(t.TabProperties.Title, tab)) {
+			return t
+		}
+		if found := findTab(t.ChildTabs, tab); found != nil {
+			return found
+		}
+	}
+	return nil
+}